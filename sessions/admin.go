@@ -0,0 +1,174 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// scanBatchSize is the number of keys requested from redis for each SCAN call
+const scanBatchSize = 100
+
+// ListSessions - returns a page of the sessions in redis, with ExpiresAt populated, along with the cursor to pass
+// in to fetch the next page. Start with a cursor of 0; a returned cursor of 0 means there are no more pages. As with
+// redis SCAN, pageSize is a hint so pages may hold more or fewer sessions, including none, and a session may be
+// returned more than once if redis is rehashing.
+func (c *Client) ListSessions(ctx context.Context, cursor uint64, pageSize int64) (sessions []*Session, next uint64, err error) {
+	ctx, op := c.startOp(ctx, opList, "")
+	defer func() { op.end(err, attrFound.Int(len(sessions))) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if pageSize <= 0 {
+		pageSize = scanBatchSize
+	}
+
+	var keys []string
+	err = c.do(ctx, true, func() (err error) {
+		keys, next, err = c.client.Scan(cursor, "*", pageSize).Result()
+		return err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis client.Scan returned an unexpected error: %w", err)
+	}
+
+	sessions, err = c.getSessions(ctx, keys)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sessions, next, nil
+}
+
+// RevokeWhere - removes every session in redis that matches the predicate, returning the number of sessions revoked.
+// All keys in the session database are scanned, so it is intended for admin and incident response use rather than
+// for serving requests. If any revoked events cannot be published the sessions are still revoked, and an error
+// wrapping ErrEventNotPublished is returned with the count.
+func (c *Client) RevokeWhere(ctx context.Context, match func(s *Session) bool) (revoked int, err error) {
+	ctx, op := c.startOp(ctx, opRevokeWhere, "")
+	defer func() { op.end(err, attrRevoked.Int(revoked)) }()
+
+	if match == nil {
+		return 0, ErrNilMatch
+	}
+
+	var unpublished error
+	err = c.scanSessions(ctx, func(s *Session) error {
+		if !match(s) {
+			return nil
+		}
+
+		// The session is removed even if its event is not published, so the scan carries on
+		err := c.delete(ctx, s)
+		if err != nil && !errors.Is(err, ErrEventNotPublished) {
+			return err
+		}
+		if err != nil {
+			unpublished = err
+		}
+
+		revoked++
+		return nil
+	})
+	if err == nil {
+		err = unpublished
+	}
+
+	return revoked, err
+}
+
+// scanSessions calls fn once for every session in redis. Email entries and keys that do not hold a session are skipped.
+func (c *Client) scanSessions(ctx context.Context, fn func(s *Session) error) error {
+	var cursor uint64
+
+	for {
+		sessions, next, err := c.ListSessions(ctx, cursor, scanBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range sessions {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// getSessions reads the sessions stored at keys along with their expiry, ignoring email entries and any keys that do
+// not hold a session
+func (c *Client) getSessions(ctx context.Context, keys []string) ([]*Session, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	peeked, err := c.peekMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(keys))
+	for i, p := range peeked {
+		if p.err != nil || p.session == nil || p.session.ID != keys[i] {
+			continue
+		}
+
+		sessions = append(sessions, p.session)
+	}
+
+	return sessions, nil
+}
+
+// PruneIndex - reconciles the expiry index with the session keys in redis, removing entries for sessions that have
+// expired or been deleted and correcting the expiry of any that are still live. It returns the IDs that were removed
+// and should be called periodically, for example from a ticker in a single instance of a service. When events are
+// enabled a SessionExpired event is published for each removed ID.
+func (c *Client) PruneIndex(ctx context.Context) (removed []string, err error) {
+	ctx, op := c.startOp(ctx, opPruneIndex, "")
+	defer func() { op.end(err, attrRemoved.Int(len(removed))) }()
+
+	keys := []string{expiryIndexKey}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+
+		var val interface{}
+		err := c.do(ctx, true, func() (err error) {
+			val, err = pruneIndexScript.Run(c.client, keys, unixMillis(c.now()), scanBatchSize, c.eventsChannel()).Result()
+			return err
+		})
+		if err != nil {
+			return removed, err
+		}
+
+		vals, ok := val.([]interface{})
+		if !ok || len(vals) == 0 {
+			return removed, fmt.Errorf("unexpected reply from prune index script: %v", val)
+		}
+
+		examined, ok := vals[0].(int64)
+		if !ok {
+			return removed, fmt.Errorf("unexpected reply from prune index script: %v", val)
+		}
+
+		for _, v := range vals[1:] {
+			if id, ok := v.(string); ok {
+				removed = append(removed, id)
+				c.metrics.session(SessionExpired, 1)
+			}
+		}
+
+		if examined < scanBatchSize {
+			return removed, nil
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	otherResp = []byte(`{"id":"5678","email":"user@other.com","start":"2020-08-13T08:40:18.652Z","last_accessed":"2020-08-13T08:40:18.652Z"}`)
+)
+
+func TestClient_ListSessions(t *testing.T) {
+	Convey("Given redis holds sessions and other keys", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.ScanFunc = func(cursor uint64, match string, count int64) *redis.ScanCmd {
+			return redis.NewScanCmdResult([]string{"1234", "user@email.com", "some-other-key"}, 42, nil)
+		}
+		mockRedisClient.EvalShaFunc = peekStore(map[string]string{
+			"1234":           string(resp),
+			"user@email.com": string(resp),
+			"some-other-key": "not a session",
+		})
+
+		Convey("When client.ListSessions is called", func() {
+			sessions, next, err := client.ListSessions(context.Background(), 0, 10)
+			So(err, ShouldBeNil)
+
+			Convey("Then a page of keys is scanned from the provided cursor", func() {
+				So(mockRedisClient.ScanCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.ScanCalls()[0].Cursor, ShouldEqual, 0)
+				So(mockRedisClient.ScanCalls()[0].Count, ShouldEqual, 10)
+			})
+
+			Convey("And the keys are read along with their TTLs in a single call", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Sha1, ShouldEqual, peekScript.Hash())
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"1234", "user@email.com", "some-other-key"})
+			})
+
+			Convey("And only the session is returned, once, with its expiry and the next cursor", func() {
+				So(sessions, ShouldHaveLength, 1)
+				So(sessions[0].ID, ShouldEqual, "1234")
+				So(sessions[0].ExpiresAt, ShouldHappenWithin, time.Second, time.Now().Add(testTTL))
+				So(next, ShouldEqual, 42)
+			})
+		})
+
+		Convey("When client.ListSessions is called without a page size", func() {
+			_, _, err := client.ListSessions(context.Background(), 42, 0)
+
+			Convey("Then the default page size is used", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.ScanCalls()[0].Cursor, ShouldEqual, 42)
+				So(mockRedisClient.ScanCalls()[0].Count, ShouldEqual, scanBatchSize)
+			})
+		})
+	})
+}
+
+func TestClient_RevokeWhere(t *testing.T) {
+	Convey("Given redis holds sessions across two pages of keys", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.ScanFunc = func(cursor uint64, match string, count int64) *redis.ScanCmd {
+			if cursor == 0 {
+				return redis.NewScanCmdResult([]string{"1234", "user@email.com"}, 7, nil)
+			}
+			return redis.NewScanCmdResult([]string{"5678", "user@other.com", "some-other-key"}, 0, nil)
+		}
+		mockRedisClient.EvalShaFunc = peekStore(map[string]string{
+			"1234":           string(resp),
+			"user@email.com": string(resp),
+			"5678":           string(otherResp),
+			"user@other.com": string(otherResp),
+		})
+
+		Convey("When client.RevokeWhere is called with a predicate matching one email domain", func() {
+			revoked, err := client.RevokeWhere(context.Background(), func(s *Session) bool {
+				return strings.HasSuffix(s.Email, "@other.com")
+			})
+
+			Convey("Then every page of keys is scanned", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.ScanCalls(), ShouldHaveLength, 2)
+				So(mockRedisClient.ScanCalls()[1].Cursor, ShouldEqual, 7)
+			})
+
+			Convey("And only the matching session is removed using both its ID and email", func() {
+				So(revoked, ShouldEqual, 1)
+				So(scriptKeys(mockRedisClient, deleteScript), ShouldResemble, [][]string{{"5678", "user@other.com", expiryIndexKey}})
+			})
+		})
+
+		Convey("When client.RevokeWhere is called with events that cannot be published", func() {
+			client.events = true
+			mockRedisClient.PublishFunc = func(channel string, message interface{}) *redis.IntCmd {
+				if channel == eventsChannel {
+					return redis.NewIntResult(0, errors.New("some redis error"))
+				}
+				return redis.NewIntResult(0, nil)
+			}
+			revoked, err := client.RevokeWhere(context.Background(), func(s *Session) bool { return true })
+
+			Convey("Then every session is still revoked, and the error says only the events were not published", func() {
+				So(errors.Is(err, ErrEventNotPublished), ShouldBeTrue)
+				So(revoked, ShouldEqual, 2)
+				So(scriptKeys(mockRedisClient, deleteScript), ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When client.RevokeWhere is called without a predicate", func() {
+			revoked, err := client.RevokeWhere(context.Background(), nil)
+
+			Convey("Then the nil match error is returned and nothing is scanned", func() {
+				So(err, ShouldEqual, ErrNilMatch)
+				So(revoked, ShouldEqual, 0)
+				So(mockRedisClient.ScanCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given redis client.Scan returns an error", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.ScanFunc = func(cursor uint64, match string, count int64) *redis.ScanCmd {
+			return redis.NewScanCmdResult(nil, 0, errors.New("some redis error"))
+		}
+
+		Convey("When client.RevokeWhere is called", func() {
+			revoked, err := client.RevokeWhere(context.Background(), func(s *Session) bool { return true })
+
+			Convey("Then the error is returned and nothing is revoked", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "redis client.Scan returned an unexpected error: some redis error")
+				So(revoked, ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given the context has been cancelled", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Convey("When client.RevokeWhere is called", func() {
+			_, err := client.RevokeWhere(ctx, func(s *Session) bool { return true })
+
+			Convey("Then the context error is returned without scanning redis", func() {
+				So(err, ShouldEqual, context.Canceled)
+				So(mockRedisClient.ScanCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+// peekStore returns a mock for redis EvalSha that answers peekScript from the provided key/payload store
+func peekStore(store map[string]string) func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+	return func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+		vals := make([]interface{}, 0, len(keys)*2)
+		for _, key := range keys {
+			payload, ok := store[key]
+			if !ok {
+				vals = append(vals, nil, int64(-2))
+				continue
+			}
+			vals = append(vals, payload, testTTL.Milliseconds())
+		}
+		return redis.NewCmdResult(vals, nil)
+	}
+}
+
+func TestClient_PruneIndex(t *testing.T) {
+	Convey("Given the expiry index holds more stale entries than fit in one batch", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			if len(mockRedisClient.EvalShaCalls()) == 1 {
+				return redis.NewCmdResult([]interface{}{int64(scanBatchSize), "1234", "5678"}, nil)
+			}
+			return redis.NewCmdResult([]interface{}{int64(1), "9999"}, nil)
+		}
+
+		Convey("When client.PruneIndex is called", func() {
+			removed, err := client.PruneIndex(context.Background())
+
+			Convey("Then the prune script is run against the expiry index until a partial batch is returned", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 2)
+				So(mockRedisClient.EvalShaCalls()[0].Sha1, ShouldEqual, pruneIndexScript.Hash())
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{expiryIndexKey})
+				So(mockRedisClient.EvalShaCalls()[0].Args[0], ShouldAlmostEqual, unixMillis(time.Now()), 1000)
+				So(mockRedisClient.EvalShaCalls()[0].Args[1], ShouldEqual, scanBatchSize)
+			})
+
+			Convey("And the IDs removed from the index are returned", func() {
+				So(removed, ShouldResemble, []string{"1234", "5678", "9999"})
+			})
+		})
+	})
+
+	Convey("Given the prune script returns an error", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, errors.New("some redis error"))
+		}
+
+		Convey("When client.PruneIndex is called", func() {
+			removed, err := client.PruneIndex(context.Background())
+
+			Convey("Then the error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "some redis error")
+				So(removed, ShouldBeEmpty)
+			})
+		})
+	})
+}
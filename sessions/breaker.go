@@ -0,0 +1,160 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable is returned without calling redis while the circuit breaker is open
+var ErrUnavailable = errors.New("redis is unavailable: circuit breaker is open")
+
+// CircuitState - the state of the circuit breaker
+type CircuitState string
+
+// Possible values for CircuitState
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerConfig - config options for the circuit breaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures to reach redis that trips the breaker
+	FailureThreshold int
+
+	// OpenTimeout is how long the breaker stays open before letting a single call through to probe for recovery
+	OpenTimeout time.Duration
+}
+
+// circuitBreaker fails calls fast once redis has been unreachable for a number of consecutive calls
+type circuitBreaker struct {
+	mu       sync.Mutex
+	cfg      CircuitBreakerConfig
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+
+	// onTransition is called with the previous and new state whenever the state changes
+	onTransition func(from, to CircuitState)
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		cfg:   cfg,
+		state: CircuitClosed,
+		now:   time.Now,
+	}
+}
+
+// allow returns ErrUnavailable if a call should not be made to redis. Once the open timeout has passed a single call
+// is allowed through as a probe while all others continue to fail fast.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.unlock(b.state)
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return ErrUnavailable
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrUnavailable
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a call that it allowed
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.unlock(b.state)
+
+	b.probing = false
+
+	if !isConnectionError(err) {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// unlock releases the lock and, if the state has changed from the given state, calls onTransition
+func (b *circuitBreaker) unlock(from CircuitState) {
+	to := b.state
+	b.mu.Unlock()
+
+	if to != from && b.onTransition != nil {
+		b.onTransition(from, to)
+	}
+}
+
+// State - returns the current state of the breaker
+func (b *circuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// isConnectionError reports whether err means redis could not be reached or could not serve the request, as opposed
+// to a successful reply such as redis.Nil or an error decoding a session
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	msg := rootCause(err).Error()
+	return msg == "redis: connection pool timeout" ||
+		msg == "redis: client is closed" ||
+		strings.HasPrefix(msg, "LOADING ")
+}
+
+// rootCause returns the innermost error wrapped by err
+func rootCause(err error) error {
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return err
+		}
+		err = unwrapped
+	}
+}
+
+// CircuitState - returns the state of the circuit breaker, which is always closed if no breaker is configured
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+
+	return c.breaker.State()
+}
@@ -0,0 +1,141 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var errConnRefused = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func TestCircuitBreaker(t *testing.T) {
+	Convey("Given a circuit breaker that trips after two failures", t, func() {
+		now := time.Now()
+		b := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Second})
+		b.now = func() time.Time { return now }
+
+		Convey("When calls fail with errors that are not connection errors", func() {
+			b.record(redis.Nil)
+			b.record(errors.New("unexpected end of JSON input"))
+
+			Convey("Then the breaker stays closed", func() {
+				So(b.State(), ShouldEqual, CircuitClosed)
+				So(b.allow(), ShouldBeNil)
+			})
+		})
+
+		Convey("When consecutive calls fail to reach redis", func() {
+			b.record(errConnRefused)
+			So(b.State(), ShouldEqual, CircuitClosed)
+			b.record(errConnRefused)
+
+			Convey("Then the breaker opens and calls fail fast", func() {
+				So(b.State(), ShouldEqual, CircuitOpen)
+				So(b.allow(), ShouldEqual, ErrUnavailable)
+			})
+
+			Convey("And once the open timeout has passed a single probe is allowed", func() {
+				now = now.Add(time.Second)
+				So(b.allow(), ShouldBeNil)
+				So(b.State(), ShouldEqual, CircuitHalfOpen)
+				So(b.allow(), ShouldEqual, ErrUnavailable)
+
+				Convey("And a successful probe closes the breaker", func() {
+					b.record(nil)
+					So(b.State(), ShouldEqual, CircuitClosed)
+					So(b.allow(), ShouldBeNil)
+				})
+
+				Convey("And a failed probe opens the breaker again", func() {
+					b.record(errConnRefused)
+					So(b.State(), ShouldEqual, CircuitOpen)
+					So(b.allow(), ShouldEqual, ErrUnavailable)
+				})
+			})
+		})
+
+		Convey("When a failure is followed by a success", func() {
+			b.record(errConnRefused)
+			b.record(nil)
+			b.record(errConnRefused)
+
+			Convey("Then the failure count is reset and the breaker stays closed", func() {
+				So(b.State(), ShouldEqual, CircuitClosed)
+			})
+		})
+	})
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	Convey("Given a client with a circuit breaker and redis is unreachable", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, errConnRefused)
+		}
+		mockRedisClient.PingFunc = func() *redis.StatusCmd {
+			return redis.NewStatusResult("", errConnRefused)
+		}
+		client.breaker = newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+
+		Convey("When sessions are read until the breaker trips", func() {
+			_, err := client.GetByID("1234")
+			So(err, ShouldEqual, errConnRefused)
+			_, err = client.GetByID("1234")
+			So(err, ShouldEqual, errConnRefused)
+			_, err = client.GetByID("1234")
+
+			Convey("Then further calls fail fast without calling redis", func() {
+				So(err, ShouldEqual, ErrUnavailable)
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 2)
+				So(client.CircuitState(), ShouldEqual, CircuitOpen)
+			})
+
+			Convey("And the health check reports the breaker is open", func() {
+				state := health.NewCheckState("redis")
+				err := client.Checker(context.Background(), state)
+				So(err, ShouldBeNil)
+				So(state.Status(), ShouldEqual, health.StatusCritical)
+				So(state.Message(), ShouldEqual, ErrUnavailable.Error())
+				So(mockRedisClient.PingCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When the breaker is half-open and another call is probing redis", func() {
+			client.breaker.state = CircuitHalfOpen
+			client.breaker.probing = true
+
+			state := health.NewCheckState("redis")
+			err := client.Checker(context.Background(), state)
+
+			Convey("Then the health check reports a warning", func() {
+				So(err, ShouldBeNil)
+				So(state.Status(), ShouldEqual, health.StatusWarning)
+				So(state.Message(), ShouldEqual, HalfOpenMessage)
+			})
+		})
+	})
+
+	Convey("Given a client without a circuit breaker", t, func() {
+		_, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("Then the circuit state is always closed", func() {
+			So(client.CircuitState(), ShouldEqual, CircuitClosed)
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package sessions
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -17,12 +19,68 @@ var (
 	ErrEmptyAddress      = errors.New("address is empty")
 	ErrEmptyPassword     = errors.New("password is empty")
 	ErrInvalidTTL        = errors.New("ttl should not be zero")
+	ErrInvalidThreshold  = errors.New("refresh threshold should be less than ttl")
+	ErrInvalidBreaker    = errors.New("circuit breaker failure threshold should be greater than zero")
+	ErrInvalidSlow       = errors.New("slow threshold should not be negative")
+	ErrNilMatch          = errors.New("match function required but was nil")
+	ErrEventNotPublished = errors.New("session event not published")
+	ErrSameSessionID     = errors.New("new session id should differ from the current one")
+	ErrSessionIDInUse    = errors.New("new session id is already in use by another session")
 )
 
+// ErrSessionNotFound is returned by the methods taking a context for a session that does not exist. The methods without
+// a context return redis.Nil instead, as they did before ErrSessionNotFound was added.
+var ErrSessionNotFound error = &notFoundError{msg: "session not found"}
+
+// notFoundError is the type of ErrSessionNotFound. It also matches redis.Nil with errors.Is, so that checks written for
+// the methods without a context keep working when callers move to the methods taking one.
+type notFoundError struct {
+	msg string
+}
+
+// Error - returns the error message
+func (e *notFoundError) Error() string {
+	return e.msg
+}
+
+// Is - reports whether target is redis.Nil
+func (e *notFoundError) Is(target error) bool {
+	return target == redis.Nil
+}
+
+// expiryIndexKey is the key of the sorted set holding every session ID scored by its expiry time in unix milliseconds
+const expiryIndexKey = "sessions:expiry-index"
+
+// legacyNotFound returns redis.Nil in place of ErrSessionNotFound, for the methods without a context
+func legacyNotFound(err error) error {
+	if err == ErrSessionNotFound {
+		return redis.Nil
+	}
+
+	return err
+}
+
+// keyNotFoundTTL is the value returned by redis PTTL when the key does not exist
+const keyNotFoundTTL = -2 * time.Millisecond
+
 // Client - structure for the redis client
 type Client struct {
-	client RedisClienter
-	ttl    time.Duration
+	client           RedisClienter
+	ttl              time.Duration
+	refreshThreshold time.Duration
+	events           bool
+	localCache       LocalCache
+	breaker          *circuitBreaker
+	retry            *RetryPolicy
+	tracer           trace.Tracer
+	metrics          *metrics
+	logger           Logger
+	logRedactionKey  []byte
+	slowThreshold    time.Duration
+	onSlowOperation  func(SlowOperation)
+	clock            func() time.Time
+	csrf             bool
+	closeFn          func() error
 }
 
 // Config - config options for the redis client
@@ -32,9 +90,56 @@ type Config struct {
 	Database int
 	TTL      time.Duration
 	TLS      *tls.Config
+
+	// RefreshThreshold is how long after its TTL was last extended that reading a session extends it again. Reads
+	// within the threshold leave the TTL alone, saving writes on busy sessions. Zero extends the TTL on every read.
+	RefreshThreshold time.Duration
+
+	// Events enables publishing of session lifecycle events, which can be received with Client.Subscribe
+	Events bool
+
+	// LocalCache is an optional in-process cache of sessions keyed by ID. The client removes entries from it when
+	// sessions are revoked by any client connected to the same redis instance. If it is a ReadThroughCache, such as
+	// one returned by NewLRUCache, GetByID reads from it before going to redis. The client subscribes to revocations
+	// when it is created, which fails if redis cannot be reached.
+	LocalCache LocalCache
+
+	// CircuitBreaker optionally fails calls fast with ErrUnavailable once redis has been unreachable for a number of
+	// consecutive calls, rather than waiting for each of them to time out
+	CircuitBreaker *CircuitBreakerConfig
+
+	// Retry optionally retries operations that fail with a transient error, such as a dropped connection
+	Retry *RetryPolicy
+
+	// TracerProvider is used to create spans for session operations. The global provider is used if it is nil.
+	TracerProvider trace.TracerProvider
+
+	// Logger optionally receives events for slow operations, retries, failures and circuit breaker transitions
+	Logger Logger
+
+	// LogRedactionKey is the secret key of the HMAC that session IDs and emails are redacted with in log events. Use
+	// the same key for every instance of a service so that their events can be correlated. If it is empty session IDs
+	// and emails are left out of log events.
+	LogRedactionKey []byte
+
+	// SlowThreshold is how long an operation can take before it is reported as slow. Slow operations are counted,
+	// logged and passed to OnSlowOperation. Zero uses a default of 250ms.
+	SlowThreshold time.Duration
+
+	// OnSlowOperation is optionally called after each operation that takes longer than SlowThreshold
+	OnSlowOperation func(SlowOperation)
+
+	// CSRF enables per-session CSRF secrets. Sessions stored without one are given one, and RotateID gives the session
+	// a new one along with its new ID.
+	CSRF bool
+
+	// Clock optionally replaces time.Now for the expiry times and LastAccessed recorded by the client, so that it can
+	// share the clock of a FakeRedis in tests
+	Clock func() time.Time
 }
 
-// NewClient - returns new redis client with provided config options
+// NewClient - returns new redis client with provided config options. Addr must be a single redis instance, such as a
+// standalone server or a primary; Redis Cluster is not supported.
 func NewClient(c Config) (*Client, error) {
 	if c.Addr == "" {
 		return nil, ErrEmptyAddress
@@ -44,34 +149,160 @@ func NewClient(c Config) (*Client, error) {
 		return nil, ErrEmptyPassword
 	}
 
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	rc := redisClient{redis.NewClient(&redis.Options{
+		Addr:      c.Addr,
+		Password:  c.Password,
+		DB:        c.Database,
+		TLSConfig: c.TLS,
+	})}
+
+	cli, err := newClient(c, rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	return cli, nil
+}
+
+// NewClientWithRedisClienter - returns new client with provided config options that uses rc to talk to redis, such as a
+// FakeRedis in tests. Addr, Password, Database and TLS are ignored.
+func NewClientWithRedisClienter(c Config, rc RedisClienter) (*Client, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	return newClient(c, rc)
+}
+
+// validate checks the config options that do not relate to the redis connection
+func (c Config) validate() error {
 	if c.TTL == 0 {
-		return nil, ErrInvalidTTL
+		return ErrInvalidTTL
+	}
+
+	if c.RefreshThreshold < 0 || c.RefreshThreshold >= c.TTL {
+		return ErrInvalidThreshold
+	}
+
+	if c.SlowThreshold < 0 {
+		return ErrInvalidSlow
 	}
 
-	return &Client{
-		client: redis.NewClient(&redis.Options{
-			Addr:      c.Addr,
-			Password:  c.Password,
-			DB:        c.Database,
-			TLSConfig: c.TLS,
-		}),
-		ttl: c.TTL,
-	}, nil
+	if c.CircuitBreaker != nil && c.CircuitBreaker.FailureThreshold <= 0 {
+		return ErrInvalidBreaker
+	}
+
+	return nil
+}
+
+// newClient returns a client using rc with the provided, validated, config options, or an error if it has a local
+// cache and cannot subscribe to revocations
+func newClient(c Config, rc RedisClienter) (*Client, error) {
+	cli := &Client{
+		client:           rc,
+		ttl:              c.TTL,
+		refreshThreshold: c.RefreshThreshold,
+		events:           c.Events,
+		localCache:       c.LocalCache,
+		retry:            c.Retry,
+		metrics:          newMetrics(),
+		logger:           c.Logger,
+		logRedactionKey:  c.LogRedactionKey,
+		slowThreshold:    c.SlowThreshold,
+		onSlowOperation:  c.OnSlowOperation,
+		clock:            c.Clock,
+		csrf:             c.CSRF,
+	}
+
+	if c.TracerProvider != nil {
+		cli.tracer = c.TracerProvider.Tracer(tracerName)
+	}
+
+	if c.CircuitBreaker != nil {
+		cli.breaker = newCircuitBreaker(*c.CircuitBreaker)
+		cli.breaker.onTransition = cli.logTransition
+	}
+
+	if cli.localCache != nil {
+		if err := cli.listenForInvalidations(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cli, nil
+}
+
+// redisClient adapts *redis.Client to the RedisClienter interface
+type redisClient struct {
+	*redis.Client
+}
+
+// Subscribe - subscribes the client to the given channels
+func (c redisClient) Subscribe(channels ...string) PubSub {
+	return c.Client.Subscribe(channels...)
+}
+
+// now returns the current time from the clock, if one is configured
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
 }
 
 // SetSession - add session to redis
 func (c *Client) SetSession(s *Session) error {
+	return c.SetSessionContext(context.Background(), s)
+}
+
+// SetSessionContext - add session to redis. If the created event cannot be published the session has still been
+// stored, and an error wrapping ErrEventNotPublished is returned.
+func (c *Client) SetSessionContext(ctx context.Context, s *Session) (err error) {
+	ctx, op := c.startOp(ctx, opSetSession, "")
+	defer func() { op.end(err) }()
+
 	if s == nil {
 		return ErrEmptySession
 	}
+	op.key = s.ID
+
+	if c.csrf && s.CSRFSecret == "" {
+		if s.CSRFSecret, err = NewCSRFSecret(); err != nil {
+			return err
+		}
+	}
 
 	sJSON, err := s.MarshalJSON()
 	if err != nil {
 		return err
 	}
 
+	if c.localCache != nil {
+		c.localCache.Remove(s.ID)
+	}
+
+	// Setting the same value again is safe, so the writes can be retried
+	err = c.do(ctx, true, func() error {
+		return c.setSession(s, sJSON)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.metrics.session(SessionCreated, 1)
+
+	return c.publishEvents(ctx, Event{Type: SessionCreated, SessionID: s.ID})
+}
+
+// setSession stores the session under both its ID and email and records its expiry in the index
+func (c *Client) setSession(s *Session, sJSON []byte) error {
 	// Add session using ID as key
-	err = c.client.Set(s.ID, sJSON, c.ttl).Err()
+	err := c.client.Set(s.ID, sJSON, c.ttl).Err()
 	if err != nil {
 		return fmt.Errorf("redis client.Set returned an unexpected error: %w", err)
 	}
@@ -82,86 +313,492 @@ func (c *Client) SetSession(s *Session) error {
 		return fmt.Errorf("redis client.Set returned an unexpected error: %w", err)
 	}
 
+	// Record session expiry in the index
+	err = c.client.ZAdd(expiryIndexKey, redis.Z{Score: float64(unixMillis(c.now().Add(c.ttl))), Member: s.ID}).Err()
+	if err != nil {
+		return fmt.Errorf("redis client.ZAdd returned an unexpected error: %w", err)
+	}
+
 	return nil
 }
 
-// GetByID - gets a session from redis using its ID
+// GetByID - gets a session from the local cache, if there is one, or from redis using its ID
 func (c *Client) GetByID(id string) (*Session, error) {
+	s, err := c.GetByIDContext(context.Background(), id)
+	return s, legacyNotFound(err)
+}
+
+// GetByIDContext - gets a session from the local cache, if there is one, or from redis using its ID
+func (c *Client) GetByIDContext(ctx context.Context, id string) (s *Session, err error) {
+	ctx, op := c.startOp(ctx, opGetByID, id)
+	localHit := false
+	defer func() { op.end(err, attrHit.Bool(s != nil), attrLocalCacheHit.Bool(localHit)) }()
+
 	if id == "" {
 		return nil, ErrEmptySessionID
 	}
 
-	msg, err := c.client.Get(id).Result()
+	cache, ok := c.localCache.(ReadThroughCache)
+	if !ok {
+		return c.getAndRefresh(ctx, id)
+	}
+
+	// Sessions in the local cache are served even while the circuit breaker is open
+	if s, localHit = cache.Get(id); localHit {
+		s.LastAccessed = c.now()
+		return s, nil
+	}
+
+	// The generation is taken before the read so that the session is not cached if it is invalidated meanwhile
+	gen := cache.Generation()
+	s, err = c.getAndRefresh(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	var s *Session
+	cache.AddIfGeneration(s, gen)
+
+	return s, nil
+}
+
+// GetByEmail - gets a session from redis using its email
+func (c *Client) GetByEmail(email string) (*Session, error) {
+	s, err := c.GetByEmailContext(context.Background(), email)
+	return s, legacyNotFound(err)
+}
+
+// GetByEmailContext - gets a session from redis using its email
+func (c *Client) GetByEmailContext(ctx context.Context, email string) (s *Session, err error) {
+	ctx, op := c.startOp(ctx, opGetByEmail, email)
+	defer func() { op.end(err, attrHit.Bool(s != nil)) }()
+
+	if email == "" {
+		return nil, ErrEmptySessionEmail
+	}
+
+	return c.getAndRefresh(ctx, email)
+}
 
-	err = json.Unmarshal([]byte(msg), &s)
+// getAndRefresh reads the session stored at key and, once the refresh threshold has passed, refreshes the TTL of both
+// its ID and email keys in a single round trip
+func (c *Client) getAndRefresh(ctx context.Context, key string) (*Session, error) {
+	keys := []string{key, expiryIndexKey}
+	now := c.now()
+	expiresAt := unixMillis(now.Add(c.ttl))
+	maxRemaining := (c.ttl - c.refreshThreshold).Milliseconds()
+	lastAccessed := formatTime(now)
+
+	var val interface{}
+	err := c.do(ctx, true, func() (err error) {
+		val, err = getAndRefreshScript.Run(c.client, keys, c.ttl.Milliseconds(), expiresAt, c.eventsChannel(), maxRemaining, lastAccessed).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Refresh TTL on access and update LastAccessed in session
-	s.LastAccessed = time.Now()
-	err = c.Expire(s.ID, c.ttl)
+	results, err := parsePeekResult(val, 1)
 	if err != nil {
 		return nil, err
 	}
 
-	err = c.Expire(s.Email, c.ttl)
+	var s *Session
+
+	err = json.Unmarshal([]byte(results[0].payload), &s)
 	if err != nil {
 		return nil, err
 	}
 
+	// The script only returns the full TTL if it has just been refreshed
+	if results[0].ttl == c.ttl {
+		c.metrics.session(SessionRefreshed, 1)
+	}
+
+	// Session was accessed so update LastAccessed in session. It is only stored in redis when the TTL is refreshed.
+	s.LastAccessed = now
+	s.ExpiresAt = s.LastAccessed.Add(results[0].ttl)
+
 	return s, nil
 }
 
-// GetByEmail - gets a session from redis using its ID
-func (c *Client) GetByEmail(email string) (*Session, error) {
+// PeekByID - gets a session and its remaining TTL from redis using its ID without refreshing its expiry
+func (c *Client) PeekByID(id string) (*Session, time.Duration, error) {
+	s, ttl, err := c.PeekByIDContext(context.Background(), id)
+	return s, ttl, legacyNotFound(err)
+}
+
+// PeekByIDContext - gets a session and its remaining TTL from redis using its ID without refreshing its expiry
+func (c *Client) PeekByIDContext(ctx context.Context, id string) (s *Session, ttl time.Duration, err error) {
+	ctx, op := c.startOp(ctx, opPeekByID, id)
+	defer func() { op.end(err, attrHit.Bool(s != nil)) }()
+
+	if id == "" {
+		return nil, 0, ErrEmptySessionID
+	}
+
+	return c.peek(ctx, id)
+}
+
+// PeekByEmail - gets a session and its remaining TTL from redis using its email without refreshing its expiry
+func (c *Client) PeekByEmail(email string) (*Session, time.Duration, error) {
+	s, ttl, err := c.PeekByEmailContext(context.Background(), email)
+	return s, ttl, legacyNotFound(err)
+}
+
+// PeekByEmailContext - gets a session and its remaining TTL from redis using its email without refreshing its expiry
+func (c *Client) PeekByEmailContext(ctx context.Context, email string) (s *Session, ttl time.Duration, err error) {
+	ctx, op := c.startOp(ctx, opPeekByEmail, email)
+	defer func() { op.end(err, attrHit.Bool(s != nil)) }()
+
 	if email == "" {
-		return nil, ErrEmptySessionEmail
+		return nil, 0, ErrEmptySessionEmail
 	}
 
-	msg, err := c.client.Get(email).Result()
+	return c.peek(ctx, email)
+}
+
+// peek reads the session stored at key along with its remaining TTL in a single round trip, leaving both the expiry
+// and LastAccessed untouched
+func (c *Client) peek(ctx context.Context, key string) (*Session, time.Duration, error) {
+	peeked, err := c.peekMany(ctx, []string{key})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return peeked[0].session, peeked[0].ttl, peeked[0].err
+}
+
+// peekedSession is a session read by peekMany with its remaining TTL, or the error reading it
+type peekedSession struct {
+	session *Session
+	ttl     time.Duration
+	err     error
+}
+
+// peekMany reads the sessions stored at keys and their remaining TTLs in a single round trip without refreshing them,
+// returning one result per key in order. Keys that do not hold a session have ErrSessionNotFound as their error, and
+// keys whose payload cannot be decoded have the JSON error.
+func (c *Client) peekMany(ctx context.Context, keys []string) ([]peekedSession, error) {
+	var val interface{}
+	err := c.do(ctx, true, func() (err error) {
+		val, err = peekScript.Run(c.client, keys).Result()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var s *Session
+	results, err := parsePeekResult(val, len(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	peeked := make([]peekedSession, len(results))
+	for i, res := range results {
+		if !res.found {
+			peeked[i].err = ErrSessionNotFound
+			continue
+		}
+
+		var s *Session
+		if err := json.Unmarshal([]byte(res.payload), &s); err != nil {
+			peeked[i].err = err
+			continue
+		}
+
+		if s != nil {
+			s.ExpiresAt = now.Add(res.ttl)
+		}
+		peeked[i] = peekedSession{session: s, ttl: res.ttl}
+	}
+
+	return peeked, nil
+}
+
+// SessionTTL - returns the TTL that sessions are stored with, and extended to when they are read
+func (c *Client) SessionTTL() time.Duration {
+	return c.ttl
+}
+
+// TTL - returns the remaining time to live of the session with the provided ID
+func (c *Client) TTL(id string) (time.Duration, error) {
+	ttl, err := c.TTLContext(context.Background(), id)
+	return ttl, legacyNotFound(err)
+}
+
+// TTLContext - returns the remaining time to live of the session with the provided ID
+func (c *Client) TTLContext(ctx context.Context, id string) (ttl time.Duration, err error) {
+	ctx, op := c.startOp(ctx, opTTL, id)
+	defer func() { op.end(err, attrHit.Bool(err == nil)) }()
+
+	if id == "" {
+		return 0, ErrEmptySessionID
+	}
+
+	err = c.do(ctx, true, func() (err error) {
+		ttl, err = c.client.PTTL(id).Result()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if ttl == keyNotFoundTTL {
+		return 0, ErrSessionNotFound
+	}
+
+	return ttl, nil
+}
+
+// GetManyByID - gets the sessions with the provided IDs from redis in a single round trip. Sessions that were found are
+// returned keyed by ID, and IDs that could not be read are returned with their error, which is redis.Nil for missing
+// sessions. Like PeekByID, it does not refresh the TTL or LastAccessed of the sessions it returns.
+func (c *Client) GetManyByID(ids []string) (map[string]*Session, map[string]error, error) {
+	sessions, errs, err := c.GetManyByIDContext(context.Background(), ids)
+	for id, idErr := range errs {
+		errs[id] = legacyNotFound(idErr)
+	}
+
+	return sessions, errs, err
+}
+
+// GetManyByIDContext - gets the sessions with the provided IDs from redis in a single round trip, as GetManyByID, with
+// ErrSessionNotFound as the error of missing sessions
+func (c *Client) GetManyByIDContext(ctx context.Context, ids []string) (sessions map[string]*Session, errs map[string]error, err error) {
+	ctx, op := c.startOp(ctx, opGetManyByID, "")
+	defer func() {
+		op.end(err, attrRequested.Int(len(ids)), attrFound.Int(len(sessions)))
+	}()
+
+	sessions = make(map[string]*Session)
+	errs = make(map[string]error)
+
+	seen := make(map[string]bool)
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			errs[id] = ErrEmptySessionID
+			continue
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		keys = append(keys, id)
+	}
+
+	if len(keys) == 0 {
+		return sessions, errs, nil
+	}
+
+	peeked, err := c.peekMany(ctx, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, id := range keys {
+		if peeked[i].err != nil {
+			errs[id] = peeked[i].err
+			continue
+		}
+
+		sessions[id] = peeked[i].session
+	}
+
+	c.metrics.lookup(opGetManyByID, len(sessions), len(keys)-len(sessions))
+
+	return sessions, errs, nil
+}
 
-	err = json.Unmarshal([]byte(msg), &s)
+// DeleteByID - removes the session with the provided ID from redis, along with its email entry if it still holds the
+// session
+func (c *Client) DeleteByID(id string) error {
+	return legacyNotFound(c.DeleteByIDContext(context.Background(), id))
+}
+
+// DeleteByIDContext - removes the session with the provided ID from redis, along with its email entry if it still
+// holds the session. If the revoked event cannot be published the session has still been removed, and an error
+// wrapping ErrEventNotPublished is returned.
+func (c *Client) DeleteByIDContext(ctx context.Context, id string) (err error) {
+	ctx, op := c.startOp(ctx, opDeleteByID, id)
+	defer func() { op.end(err) }()
+
+	if id == "" {
+		return ErrEmptySessionID
+	}
+
+	s, _, err := c.peek(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.delete(ctx, s)
+}
+
+// RotateID - moves the session with the provided ID to newID, giving it a new CSRF secret if CSRF is enabled, and
+// returns it. The old ID no longer finds the session. newID should be a new random ID, as with any session ID.
+func (c *Client) RotateID(id, newID string) (*Session, error) {
+	s, err := c.RotateIDContext(context.Background(), id, newID)
+	return s, legacyNotFound(err)
+}
+
+// RotateIDContext - moves the session with the provided ID to newID, giving it a new CSRF secret if CSRF is enabled,
+// and returns it. The old ID no longer finds the session. newID should be a new random ID, as with any session ID:
+// ErrSameSessionID is returned if it is the current ID and ErrSessionIDInUse if another session already has it, which is
+// checked in the same script that moves the session. If the lifecycle events cannot be published the session has still
+// been moved, and is returned with an error wrapping ErrEventNotPublished.
+func (c *Client) RotateIDContext(ctx context.Context, id, newID string) (s *Session, err error) {
+	ctx, op := c.startOp(ctx, opRotateID, id)
+	defer func() { op.end(err) }()
+
+	if id == "" || newID == "" {
+		return nil, ErrEmptySessionID
+	}
+
+	// Moving a session to its own ID would store it and then delete it
+	if newID == id {
+		return nil, ErrSameSessionID
+	}
+
+	s, _, err = c.peek(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Refresh TTL on access and update LastAccessed in session
-	s.LastAccessed = time.Now()
-	err = c.Expire(s.Email, c.ttl)
+	s.ID = newID
+	if c.csrf {
+		if s.CSRFSecret, err = NewCSRFSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	sJSON, err := s.MarshalJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	err = c.Expire(s.ID, c.ttl)
+	// The script finds the session already moved if a retry follows an attempt whose reply was lost, so is safe to repeat
+	now := c.now()
+	keys := []string{newID, s.Email, expiryIndexKey, id}
+
+	var moved int64
+	err = c.do(ctx, true, func() (err error) {
+		moved, err = rotateScript.Run(c.client, keys, sJSON, c.ttl.Milliseconds(), unixMillis(now.Add(c.ttl))).Int64()
+		if err != nil || moved != 1 {
+			return err
+		}
+
+		return c.invalidate(id)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return s, nil
+	switch moved {
+	case 0:
+		return nil, ErrSessionIDInUse
+	case -1:
+		return nil, ErrSessionNotFound
+	}
+
+	s.ExpiresAt = now.Add(c.ttl)
+
+	c.metrics.session(SessionRevoked, 1)
+	c.metrics.session(SessionCreated, 1)
+
+	return s, c.publishEvents(ctx,
+		Event{Type: SessionRevoked, SessionID: id},
+		Event{Type: SessionCreated, SessionID: newID},
+	)
+}
+
+// delete removes the session's ID entry from redis, and its email entry unless it has been taken over by a newer
+// session, and broadcasts the revocation
+func (c *Client) delete(ctx context.Context, s *Session) error {
+	// Deleting and invalidating again is safe, so only the event is not retried
+	err := c.do(ctx, true, func() error {
+		err := deleteScript.Run(c.client, []string{s.ID, s.Email, expiryIndexKey}).Err()
+		if err != nil {
+			return err
+		}
+
+		return c.invalidate(s.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.metrics.session(SessionRevoked, 1)
+
+	return c.publishEvents(ctx, Event{Type: SessionRevoked, SessionID: s.ID})
 }
 
 // DeleteAll - removes all items from redis
 func (c *Client) DeleteAll() error {
-	return c.client.FlushAll().Err()
+	return c.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext - removes all items from redis
+func (c *Client) DeleteAllContext(ctx context.Context) (err error) {
+	ctx, op := c.startOp(ctx, opDeleteAll, "")
+	defer func() { op.end(err) }()
+
+	return c.do(ctx, true, func() error {
+		err := c.client.FlushAll().Err()
+		if err != nil {
+			return err
+		}
+
+		return c.invalidate(invalidateAll)
+	})
 }
 
 // Ping - checks the connection to redis
 func (c *Client) Ping() error {
-	return c.client.Ping().Err()
+	return c.PingContext(context.Background())
+}
+
+// PingContext - checks the connection to redis
+func (c *Client) PingContext(ctx context.Context) (err error) {
+	ctx, op := c.startOp(ctx, opPing, "")
+	defer func() { op.end(err) }()
+
+	return c.do(ctx, true, func() error {
+		return c.client.Ping().Err()
+	})
+}
+
+// Close - stops listening for revocations from other clients and closes the connection to redis, returning any errors
+// from both
+func (c *Client) Close() error {
+	var closeErr error
+	if c.closeFn != nil {
+		closeErr = c.closeFn()
+	}
+
+	return errors.Join(closeErr, c.client.Close())
 }
 
 // Expire - sets the expiration of key
 func (c *Client) Expire(key string, expiration time.Duration) error {
-	return c.client.Expire(key, expiration).Err()
+	return c.ExpireContext(context.Background(), key, expiration)
+}
+
+// ExpireContext - sets the expiration of key
+func (c *Client) ExpireContext(ctx context.Context, key string, expiration time.Duration) (err error) {
+	ctx, op := c.startOp(ctx, opExpire, key)
+	defer func() { op.end(err) }()
+
+	return c.do(ctx, true, func() error {
+		return c.client.Expire(key, expiration).Err()
+	})
+}
+
+// unixMillis returns t as the number of milliseconds since the unix epoch
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
 }
@@ -0,0 +1,81 @@
+package sessions_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+	"github.com/go-redis/redis"
+)
+
+// The benchmarks run against the server selected by redisServerEnv, as the integration tests do, so that both paths
+// pay for real round trips. miniredis runs lua in a much slower interpreter than redis, which penalises GetByID, so
+// compare the paths with SESSIONS_REDIS_SERVER set to a redis-server.
+
+// newBenchTarget stores a session in a new integration target and returns a sessions client and a raw redis client
+// for it
+func newBenchTarget(b *testing.B) (*sessions.Client, *redis.Client, *sessions.Session) {
+	target := newIntegrationTarget(b)
+	client := target.client(b)
+	rc := target.rawClient()
+	b.Cleanup(func() { rc.Close() })
+
+	s := &sessions.Session{ID: "1234", Email: "user@email.com", Start: target.now(), LastAccessed: target.now()}
+	if err := client.SetSession(s); err != nil {
+		b.Fatal(err)
+	}
+
+	return client, rc, s
+}
+
+// getByIDSequential is the previous implementation of GetByID, which fetched the session and refreshed each key's
+// TTL with separate calls to redis
+func getByIDSequential(rc *redis.Client, id string, ttl time.Duration) (*sessions.Session, error) {
+	msg, err := rc.Get(id).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *sessions.Session
+
+	err = json.Unmarshal([]byte(msg), &s)
+	if err != nil {
+		return nil, err
+	}
+
+	s.LastAccessed = time.Now()
+	err = rc.Expire(s.ID, ttl).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = rc.Expire(s.Email, ttl).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func BenchmarkClient_GetByID(b *testing.B) {
+	client, _, s := newBenchTarget(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.GetByID(s.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkClient_GetByIDSequential(b *testing.B) {
+	_, rc, s := newBenchTarget(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := getByIDSequential(rc, s.ID, integrationTTL); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
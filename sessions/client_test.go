@@ -1,6 +1,7 @@
 package sessions
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -94,6 +95,44 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestNewClient_RefreshThreshold(t *testing.T) {
+	Convey("Given NewClient returns an error", t, func() {
+
+		Convey("When the refresh threshold is not less than the ttl", func() {
+			c, err := NewClient(Config{
+				Addr:             "123.0.0.1",
+				Password:         "1234",
+				TTL:              testTTL,
+				RefreshThreshold: testTTL,
+			})
+
+			Convey("Then the client will not be created and the invalid threshold error is returned", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldEqual, ErrInvalidThreshold)
+			})
+		})
+	})
+}
+
+func TestNewClient_CircuitBreaker(t *testing.T) {
+	Convey("Given NewClient returns an error", t, func() {
+
+		Convey("When the circuit breaker failure threshold is zero", func() {
+			c, err := NewClient(Config{
+				Addr:           "123.0.0.1",
+				Password:       "1234",
+				TTL:            testTTL,
+				CircuitBreaker: &CircuitBreakerConfig{OpenTimeout: time.Second},
+			})
+
+			Convey("Then the client will not be created and the invalid breaker error is returned", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldEqual, ErrInvalidBreaker)
+			})
+		})
+	})
+}
+
 func TestClient_Set(t *testing.T) {
 	Convey("Given a valid sessions and redis client.Set returns no error", t, func() {
 		mockRedisClient, client := setUpMocks(
@@ -124,6 +163,13 @@ func TestClient_Set(t *testing.T) {
 				So(mockRedisClient.SetCalls()[0].Value, ShouldResemble, jsonByes)
 				So(mockRedisClient.SetCalls()[0].Expiration, ShouldEqual, testTTL)
 			})
+
+			Convey("And the session expiry is recorded in the expiry index", func() {
+				So(mockRedisClient.ZAddCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.ZAddCalls()[0].Key, ShouldEqual, expiryIndexKey)
+				So(mockRedisClient.ZAddCalls()[0].Members[0].Member, ShouldEqual, s.ID)
+				So(mockRedisClient.ZAddCalls()[0].Members[0].Score, ShouldAlmostEqual, unixMillis(time.Now().Add(testTTL)), 1000)
+			})
 		})
 	})
 
@@ -185,33 +231,65 @@ func TestClient_GetByID(t *testing.T) {
 	Convey("Given a session ID client.GetByID returns a session and TTL is refreshed", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
-			*redis.NewStringResult(string(resp), nil),
+			*redis.NewStringCmd(),
 			*redis.NewStatusCmd(),
 			*redis.NewBoolCmd(),
 		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+		}
 
 		Convey("When client uses the ID to get the session", func() {
 			s, err := client.GetByID("1234")
 			So(err, ShouldBeNil)
 
-			Convey("Then redis client.Get is called with the expected parameters", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 1)
-				So(mockRedisClient.GetCalls()[0].Key, ShouldEqual, "1234")
+			Convey("Then the session is fetched and refreshed in a single call to redis", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Sha1, ShouldEqual, getAndRefreshScript.Hash())
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"1234", expiryIndexKey})
+				So(mockRedisClient.EvalShaCalls()[0].Args, ShouldHaveLength, 5)
+				So(mockRedisClient.EvalShaCalls()[0].Args[0], ShouldEqual, testTTL.Milliseconds())
+				So(mockRedisClient.EvalShaCalls()[0].Args[1], ShouldAlmostEqual, unixMillis(time.Now().Add(testTTL)), 1000)
+				So(mockRedisClient.EvalShaCalls()[0].Args[2], ShouldEqual, "")
+				So(mockRedisClient.EvalShaCalls()[0].Args[3], ShouldEqual, testTTL.Milliseconds())
+				So(mockRedisClient.EvalShaCalls()[0].Args[4], ShouldEqual, formatTime(s.LastAccessed))
 
-				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 2) // Expects 2 due to refreshing by ID and Email
-
-				So(mockRedisClient.ExpireCalls()[0].Key, ShouldEqual, "1234")
-				So(mockRedisClient.ExpireCalls()[0].Expiration, ShouldEqual, testTTL)
-
-				So(mockRedisClient.ExpireCalls()[1].Key, ShouldEqual, "user@email.com")
-				So(mockRedisClient.ExpireCalls()[1].Expiration, ShouldEqual, testTTL)
+				So(mockRedisClient.GetCalls(), ShouldHaveLength, 0)
+				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 0)
 			})
 
 			Convey("And the expected session is returned", func() {
 				So(s, ShouldNotBeEmpty)
 				So(s.ID, ShouldEqual, "1234")
 				So(s.LastAccessed.String(), ShouldNotEqual, respLastAccessed)
-				So(mockRedisClient.ExpireCalls()[0].Expiration, ShouldEqual, testTTL)
+				So(s.ExpiresAt, ShouldEqual, s.LastAccessed.Add(testTTL))
+			})
+		})
+	})
+
+	Convey("Given a client with a refresh threshold and a session that was refreshed within it", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		client.refreshThreshold = 5 * time.Minute
+		remaining := testTTL - time.Minute
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), remaining.Milliseconds()}, nil)
+		}
+
+		Convey("When client uses the ID to get the session", func() {
+			s, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the script is only asked to refresh the TTL once the threshold has passed", func() {
+				So(mockRedisClient.EvalShaCalls()[0].Args[3], ShouldEqual, (testTTL - 5*time.Minute).Milliseconds())
+			})
+
+			Convey("And the session expiry reflects the remaining TTL", func() {
+				So(s.ExpiresAt, ShouldEqual, s.LastAccessed.Add(remaining))
 			})
 		})
 	})
@@ -219,18 +297,20 @@ func TestClient_GetByID(t *testing.T) {
 	Convey("Given a session ID client.GetByID returns an error", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
-			*redis.NewStringResult(string(resp), nil),
+			*redis.NewStringCmd(),
 			*redis.NewStatusCmd(),
-			*redis.NewBoolResult(false, errors.New("unable to refresh expiration")),
+			*redis.NewBoolCmd(),
 		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, errors.New("unable to refresh expiration"))
+		}
 
 		Convey("When client uses the ID to get the session", func() {
 			s, err := client.GetByID("1234")
 
-			Convey("Then redis client.Get is called with the expected parameters", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 1)
-				So(mockRedisClient.GetCalls()[0].Key, ShouldEqual, "1234")
-				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 1)
+			Convey("Then redis is called with the expected parameters", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"1234", expiryIndexKey})
 			})
 
 			Convey("And the expected error is returned", func() {
@@ -241,6 +321,40 @@ func TestClient_GetByID(t *testing.T) {
 		})
 	})
 
+	Convey("Given a session ID that does not exist in redis", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, redis.Nil)
+		}
+
+		Convey("When client.GetByID is called", func() {
+			s, err := client.GetByID("1234")
+
+			Convey("Then redis.Nil is returned and no session is returned", func() {
+				So(err, ShouldEqual, redis.Nil)
+				So(s, ShouldBeNil)
+			})
+		})
+
+		Convey("When client.GetByIDContext is called", func() {
+			s, err := client.GetByIDContext(context.Background(), "1234")
+
+			Convey("Then ErrSessionNotFound is returned and no session is returned", func() {
+				So(err, ShouldEqual, ErrSessionNotFound)
+				So(s, ShouldBeNil)
+			})
+
+			Convey("And the error still matches redis.Nil for callers that check for it", func() {
+				So(errors.Is(err, redis.Nil), ShouldBeTrue)
+			})
+		})
+	})
+
 	Convey("Given a blank session ID client.GetByID returns an error", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
@@ -253,7 +367,7 @@ func TestClient_GetByID(t *testing.T) {
 			s, err := client.GetByID("")
 
 			Convey("Then client.GetByID returns an error and no session is returned", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 0)
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 0)
 				So(s, ShouldBeNil)
 				So(err, ShouldNotBeEmpty)
 				So(err, ShouldEqual, ErrEmptySessionID)
@@ -261,19 +375,22 @@ func TestClient_GetByID(t *testing.T) {
 		})
 	})
 
-	Convey("Given a session ID client.GetByID returns an error", t, func() {
+	Convey("Given a session ID client.GetByID returns an invalid session", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
-			*redis.NewStringResult("", errors.New("unexpected end of JSON input")),
+			*redis.NewStringCmd(),
 			*redis.NewStatusCmd(),
 			*redis.NewBoolCmd(),
 		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{"", testTTL.Milliseconds()}, nil)
+		}
 
 		Convey("When client.GetByID is called with a valid session ID", func() {
 			s, err := client.GetByID("1234")
 
-			Convey("Then the redis client.Get returns an error and no session is returned", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 1)
+			Convey("Then the JSON error is returned and no session is returned", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
 				So(s, ShouldBeNil)
 				So(err, ShouldNotBeEmpty)
 				So(err.Error(), ShouldEqual, "unexpected end of JSON input")
@@ -286,25 +403,23 @@ func TestClient_GetByEmail(t *testing.T) {
 	Convey("Given a session email client.GetByEmail returns a session and TTL is refreshed", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
-			*redis.NewStringResult(string(resp), nil),
+			*redis.NewStringCmd(),
 			*redis.NewStatusCmd(),
 			*redis.NewBoolCmd(),
 		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+		}
 
 		Convey("When client uses the email to get the session", func() {
 			s, err := client.GetByEmail("user@email.com")
 			So(err, ShouldBeNil)
 
-			Convey("Then redis client.Get is called with the expected parameters", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 1)
-				So(mockRedisClient.GetCalls()[0].Key, ShouldEqual, "user@email.com")
-
-				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 2) // Expects 2 due to refreshing by ID and Email
-				So(mockRedisClient.ExpireCalls()[0].Key, ShouldEqual, "user@email.com")
-				So(mockRedisClient.ExpireCalls()[0].Expiration, ShouldEqual, testTTL)
-
-				So(mockRedisClient.ExpireCalls()[1].Key, ShouldEqual, "1234")
-				So(mockRedisClient.ExpireCalls()[1].Expiration, ShouldEqual, testTTL)
+			Convey("Then the session is fetched and refreshed in a single call to redis", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Sha1, ShouldEqual, getAndRefreshScript.Hash())
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"user@email.com", expiryIndexKey})
+				So(mockRedisClient.EvalShaCalls()[0].Args[0], ShouldEqual, testTTL.Milliseconds())
 			})
 
 			Convey("And the expected session is returned", func() {
@@ -319,24 +434,23 @@ func TestClient_GetByEmail(t *testing.T) {
 	Convey("Given a session email client.GetByEmail returns an error", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
-			*redis.NewStringResult(string(resp), nil),
+			*redis.NewStringCmd(),
 			*redis.NewStatusCmd(),
-			*redis.NewBoolResult(false, errors.New("unable to refresh expiration")),
+			*redis.NewBoolCmd(),
 		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, errors.New("unable to refresh expiration"))
+		}
 
 		Convey("When client uses the email to get the session", func() {
 			s, err := client.GetByEmail("user@email.com")
 
-			Convey("Then redis client.Get is called with the expected parameters", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 1)
-				So(mockRedisClient.GetCalls()[0].Key, ShouldEqual, "user@email.com")
-
-				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 1) // Expects 2 due to refreshing by ID and Email
-				So(mockRedisClient.ExpireCalls()[0].Key, ShouldEqual, "user@email.com")
-				So(mockRedisClient.ExpireCalls()[0].Expiration, ShouldEqual, testTTL)
+			Convey("Then redis is called with the expected parameters", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"user@email.com", expiryIndexKey})
 			})
 
-			Convey("Then redis client.Get is called and returns an error", func() {
+			Convey("Then redis is called and returns an error", func() {
 				So(err, ShouldNotBeEmpty)
 				So(err.Error(), ShouldEqual, "unable to refresh expiration")
 				So(s, ShouldBeNil)
@@ -356,7 +470,7 @@ func TestClient_GetByEmail(t *testing.T) {
 			s, err := client.GetByEmail("")
 
 			Convey("Then client.GetByEmail returns an error and no session is returned", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 0)
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 0)
 				So(s, ShouldBeNil)
 				So(err, ShouldNotBeEmpty)
 				So(err, ShouldEqual, ErrEmptySessionEmail)
@@ -367,21 +481,23 @@ func TestClient_GetByEmail(t *testing.T) {
 	Convey("Given a session ID client.GetByEmail returns an error", t, func() {
 		mockRedisClient, client := setUpMocks(
 			*redis.NewStatusCmd(),
-			*redis.NewStringResult("", errors.New("unexpected end of JSON input")),
+			*redis.NewStringCmd(),
 			*redis.NewStatusCmd(),
 			*redis.NewBoolCmd(),
 		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{"", testTTL.Milliseconds()}, nil)
+		}
 
 		Convey("When client.GetByEmail is called with a valid session ID", func() {
 			s, err := client.GetByEmail("user@test.com")
 
-			Convey("Then redis client.Get is called with the expected parameters", func() {
-				So(mockRedisClient.GetCalls(), ShouldHaveLength, 1)
-				So(mockRedisClient.GetCalls()[0].Key, ShouldEqual, "user@test.com")
-				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 0) // Expects 2 due to refreshing by ID and Email
+			Convey("Then redis is called with the expected parameters", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"user@test.com", expiryIndexKey})
 			})
 
-			Convey("Then the redis client.Get returns an error and no session is returned", func() {
+			Convey("Then the JSON error is returned and no session is returned", func() {
 				So(s, ShouldBeNil)
 				So(err, ShouldNotBeEmpty)
 				So(err.Error(), ShouldEqual, "unexpected end of JSON input")
@@ -390,6 +506,385 @@ func TestClient_GetByEmail(t *testing.T) {
 	})
 }
 
+func TestClient_PeekByID(t *testing.T) {
+	Convey("Given a session ID client.PeekByID returns a session and its TTL", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), int64(600000)}, nil)
+		}
+
+		Convey("When client uses the ID to peek at the session", func() {
+			s, ttl, err := client.PeekByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the peek script is run once with the session ID", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Sha1, ShouldEqual, peekScript.Hash())
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"1234"})
+				So(mockRedisClient.GetCalls(), ShouldHaveLength, 0)
+			})
+
+			Convey("And the session expiry is not refreshed", func() {
+				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 0)
+			})
+
+			Convey("And the expected session and TTL are returned", func() {
+				So(s, ShouldNotBeEmpty)
+				So(s.ID, ShouldEqual, "1234")
+				So(s.Email, ShouldEqual, "user@email.com")
+				So(ttl, ShouldEqual, 10*time.Minute)
+				So(s.ExpiresAt, ShouldHappenWithin, time.Second, time.Now().Add(10*time.Minute))
+			})
+		})
+	})
+
+	Convey("Given the script is not cached by redis", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, errors.New("NOSCRIPT No matching script. Please use EVAL."))
+		}
+		mockRedisClient.EvalFunc = func(script string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), int64(600000)}, nil)
+		}
+
+		Convey("When client.PeekByID is called", func() {
+			s, _, err := client.PeekByID("1234")
+
+			Convey("Then the script is sent to redis with EVAL and the session is returned", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.EvalCalls(), ShouldHaveLength, 1)
+				So(s.ID, ShouldEqual, "1234")
+			})
+		})
+	})
+
+	Convey("Given a session ID that does not exist in redis", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{nil, int64(-2)}, nil)
+		}
+
+		Convey("When client.PeekByID is called", func() {
+			s, ttl, err := client.PeekByID("1234")
+
+			Convey("Then redis.Nil is returned and no session is returned", func() {
+				So(err, ShouldEqual, redis.Nil)
+				So(s, ShouldBeNil)
+				So(ttl, ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given a blank session ID client.PeekByID returns an error", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("When client.PeekByID is called with an empty ID", func() {
+			s, _, err := client.PeekByID("")
+
+			Convey("Then client.PeekByID returns an error and no session is returned", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 0)
+				So(s, ShouldBeNil)
+				So(err, ShouldEqual, ErrEmptySessionID)
+			})
+		})
+	})
+}
+
+func TestClient_PeekByEmail(t *testing.T) {
+	Convey("Given a session email client.PeekByEmail returns a session and its TTL", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), int64(300000)}, nil)
+		}
+
+		Convey("When client uses the email to peek at the session", func() {
+			s, ttl, err := client.PeekByEmail("user@email.com")
+			So(err, ShouldBeNil)
+
+			Convey("Then the peek script is run with the email and the expiry is not refreshed", func() {
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"user@email.com"})
+				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 0)
+			})
+
+			Convey("And the expected session and TTL are returned", func() {
+				So(s.Email, ShouldEqual, "user@email.com")
+				So(ttl, ShouldEqual, 5*time.Minute)
+			})
+		})
+	})
+
+	Convey("Given a blank session email client.PeekByEmail returns an error", t, func() {
+		_, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("When client.PeekByEmail is called with an empty email", func() {
+			s, _, err := client.PeekByEmail("")
+
+			Convey("Then client.PeekByEmail returns an error and no session is returned", func() {
+				So(s, ShouldBeNil)
+				So(err, ShouldEqual, ErrEmptySessionEmail)
+			})
+		})
+	})
+}
+
+func TestClient_TTL(t *testing.T) {
+	Convey("Given a session ID client.TTL returns the remaining TTL", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.PTTLFunc = func(key string) *redis.DurationCmd {
+			return redis.NewDurationResult(10*time.Minute, nil)
+		}
+
+		Convey("When client.TTL is called", func() {
+			ttl, err := client.TTL("1234")
+
+			Convey("Then redis client.PTTL is called with the session ID and the TTL is returned", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.PTTLCalls()[0].Key, ShouldEqual, "1234")
+				So(ttl, ShouldEqual, 10*time.Minute)
+			})
+		})
+	})
+
+	Convey("Given a session ID that does not exist in redis", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.PTTLFunc = func(key string) *redis.DurationCmd {
+			return redis.NewDurationResult(keyNotFoundTTL, nil)
+		}
+
+		Convey("When client.TTL is called", func() {
+			_, err := client.TTL("1234")
+
+			Convey("Then redis.Nil is returned", func() {
+				So(err, ShouldEqual, redis.Nil)
+			})
+		})
+	})
+
+	Convey("Given a blank session ID client.TTL returns an error", t, func() {
+		_, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("When client.TTL is called with an empty ID", func() {
+			_, err := client.TTL("")
+
+			Convey("Then the empty session ID error is returned", func() {
+				So(err, ShouldEqual, ErrEmptySessionID)
+			})
+		})
+	})
+}
+
+func TestClient_GetManyByID(t *testing.T) {
+	Convey("Given redis holds some of the requested sessions", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{
+				string(resp), int64(600000),
+				nil, int64(-2),
+				"not json", int64(600000),
+			}, nil)
+		}
+
+		Convey("When client.GetManyByID is called", func() {
+			sessions, errs, err := client.GetManyByID([]string{"1234", "5678", "1234", "", "9999"})
+			So(err, ShouldBeNil)
+
+			Convey("Then all the unique IDs are fetched from redis in a single call", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.EvalShaCalls()[0].Sha1, ShouldEqual, peekScript.Hash())
+				So(mockRedisClient.EvalShaCalls()[0].Keys, ShouldResemble, []string{"1234", "5678", "9999"})
+				So(mockRedisClient.ExpireCalls(), ShouldHaveLength, 0)
+			})
+
+			Convey("And the sessions that were found are returned", func() {
+				So(sessions, ShouldHaveLength, 1)
+				So(sessions["1234"].Email, ShouldEqual, "user@email.com")
+				So(sessions["1234"].ExpiresAt, ShouldHappenWithin, time.Second, time.Now().Add(10*time.Minute))
+			})
+
+			Convey("And an error is returned for each ID that could not be read", func() {
+				So(errs, ShouldHaveLength, 3)
+				So(errs[""], ShouldEqual, ErrEmptySessionID)
+				So(errs["5678"], ShouldEqual, redis.Nil)
+				So(errs["9999"], ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given redis returns an error", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult(nil, errors.New("some redis error"))
+		}
+
+		Convey("When client.GetManyByID is called", func() {
+			sessions, errs, err := client.GetManyByID([]string{"1234"})
+
+			Convey("Then the error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "some redis error")
+				So(sessions, ShouldBeNil)
+				So(errs, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given no IDs are provided", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("When client.GetManyByID is called", func() {
+			sessions, errs, err := client.GetManyByID(nil)
+
+			Convey("Then redis is not called and nothing is returned", func() {
+				So(err, ShouldBeNil)
+				So(sessions, ShouldBeEmpty)
+				So(errs, ShouldBeEmpty)
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestClient_DeleteByID(t *testing.T) {
+	Convey("Given a session ID that exists in redis", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), int64(600000)}, nil)
+		}
+
+		Convey("When client.DeleteByID is called", func() {
+			err := client.DeleteByID("1234")
+
+			Convey("Then the ID and email entries and the expiry index entry are removed by the delete script", func() {
+				So(err, ShouldBeNil)
+				So(scriptKeys(mockRedisClient, deleteScript), ShouldResemble, [][]string{{"1234", "user@email.com", expiryIndexKey}})
+			})
+		})
+	})
+
+	Convey("Given an older and a newer session for the same email", t, func() {
+		fake := newFakeRedis()
+		client := newFakeClient(fake, Config{})
+		So(client.SetSession(&Session{ID: "old", Email: "user@email.com"}), ShouldBeNil)
+		So(client.SetSession(&Session{ID: "new", Email: "user@email.com"}), ShouldBeNil)
+
+		Convey("When the older session is deleted", func() {
+			So(client.DeleteByID("old"), ShouldBeNil)
+
+			Convey("Then the email still finds the newer session", func() {
+				_, _, err := client.PeekByID("old")
+				So(err, ShouldEqual, redis.Nil)
+
+				s, _, err := client.PeekByEmail("user@email.com")
+				So(err, ShouldBeNil)
+				So(s.ID, ShouldEqual, "new")
+			})
+		})
+	})
+
+	Convey("Given a session ID that does not exist in redis", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{nil, int64(-2)}, nil)
+		}
+
+		Convey("When client.DeleteByID is called", func() {
+			err := client.DeleteByID("1234")
+
+			Convey("Then redis.Nil is returned and nothing is deleted", func() {
+				So(err, ShouldEqual, redis.Nil)
+				So(scriptKeys(mockRedisClient, deleteScript), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a blank session ID", t, func() {
+		_, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("When client.DeleteByID is called", func() {
+			err := client.DeleteByID("")
+
+			Convey("Then the empty session ID error is returned", func() {
+				So(err, ShouldEqual, ErrEmptySessionID)
+			})
+		})
+	})
+}
+
 func TestClient_DeleteAll(t *testing.T) {
 	Convey("Given DeleteAll removes all sessions from cache", t, func() {
 		mockRedisClient, client := setUpMocks(
@@ -406,6 +901,12 @@ func TestClient_DeleteAll(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(mockRedisClient.FlushAllCalls(), ShouldHaveLength, 1)
 			})
+
+			Convey("And the removal of every session is broadcast to other clients", func() {
+				So(mockRedisClient.PublishCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.PublishCalls()[0].Channel, ShouldEqual, invalidationsChannel)
+				So(mockRedisClient.PublishCalls()[0].Message, ShouldEqual, invalidateAll)
+			})
 		})
 	})
 
@@ -430,6 +931,17 @@ func TestClient_DeleteAll(t *testing.T) {
 	})
 }
 
+// scriptKeys returns the keys of each call to redis EvalSha that ran script
+func scriptKeys(mockRedisClient *RedisClienterMock, script *redis.Script) [][]string {
+	var keys [][]string
+	for _, call := range mockRedisClient.EvalShaCalls() {
+		if call.Sha1 == script.Hash() {
+			keys = append(keys, call.Keys)
+		}
+	}
+	return keys
+}
+
 func setUpMocks(setStatusCmd redis.StatusCmd, getStringCmd redis.StringCmd, flushAllStatusCmd redis.StatusCmd, expireBoolCmd redis.BoolCmd) (*RedisClienterMock, *Client) {
 	mockRedisClient := &RedisClienterMock{
 		PingFunc: nil,
@@ -444,6 +956,15 @@ func setUpMocks(setStatusCmd redis.StatusCmd, getStringCmd redis.StringCmd, flus
 		},
 		ExpireFunc: func(key string, expiration time.Duration) *redis.BoolCmd {
 			return &expireBoolCmd
+		},
+		ZAddFunc: func(key string, members ...redis.Z) *redis.IntCmd {
+			return redis.NewIntResult(int64(len(members)), nil)
+		},
+		ZRemFunc: func(key string, members ...interface{}) *redis.IntCmd {
+			return redis.NewIntResult(int64(len(members)), nil)
+		},
+		PublishFunc: func(channel string, message interface{}) *redis.IntCmd {
+			return redis.NewIntResult(0, nil)
 		}}
 	return mockRedisClient, &Client{
 		client: mockRedisClient,
@@ -0,0 +1,81 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrNoCSRFSecret is returned when a CSRF token is requested for a session without a CSRF secret
+var ErrNoCSRFSecret = errors.New("session has no csrf secret")
+
+// csrfSecretLength is the number of random bytes in a CSRF secret
+const csrfSecretLength = 32
+
+// NewCSRFSecret - returns a new random CSRF secret for a session
+func NewCSRFSecret() (string, error) {
+	b := make([]byte, csrfSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CSRFToken - returns a CSRF token for the session. Tokens are masked with a random pad so that every one is
+// different, which stops the secret being recovered from compressed responses, but they all verify against the
+// session's secret.
+func (s *Session) CSRFToken() (string, error) {
+	secret, err := s.csrfSecret()
+	if err != nil {
+		return "", err
+	}
+
+	token := make([]byte, 2*len(secret))
+	pad, masked := token[:len(secret)], token[len(secret):]
+	if _, err := rand.Read(pad); err != nil {
+		return "", err
+	}
+
+	for i := range secret {
+		masked[i] = secret[i] ^ pad[i]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// VerifyCSRFToken - reports whether token is a CSRF token issued for the session
+func (s *Session) VerifyCSRFToken(token string) bool {
+	secret, err := s.csrfSecret()
+	if err != nil {
+		return false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != 2*len(secret) {
+		return false
+	}
+
+	pad, masked := b[:len(secret)], b[len(secret):]
+	unmasked := make([]byte, len(secret))
+	for i := range secret {
+		unmasked[i] = masked[i] ^ pad[i]
+	}
+
+	return subtle.ConstantTimeCompare(unmasked, secret) == 1
+}
+
+// csrfSecret returns the decoded CSRF secret of the session
+func (s *Session) csrfSecret() ([]byte, error) {
+	if s.CSRFSecret == "" {
+		return nil, ErrNoCSRFSecret
+	}
+
+	secret, err := base64.RawURLEncoding.DecodeString(s.CSRFSecret)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrNoCSRFSecret
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,201 @@
+package sessions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSession_CSRFToken(t *testing.T) {
+	Convey("Given a session with a CSRF secret", t, func() {
+		secret, err := NewCSRFSecret()
+		So(err, ShouldBeNil)
+		s := &Session{ID: "1234", CSRFSecret: secret}
+
+		Convey("When two tokens are issued", func() {
+			first, err := s.CSRFToken()
+			So(err, ShouldBeNil)
+			second, err := s.CSRFToken()
+			So(err, ShouldBeNil)
+
+			Convey("Then they are masked differently but both verify", func() {
+				So(first, ShouldNotEqual, second)
+				So(s.VerifyCSRFToken(first), ShouldBeTrue)
+				So(s.VerifyCSRFToken(second), ShouldBeTrue)
+			})
+
+			Convey("And they do not verify for a session with another secret", func() {
+				other, err := NewCSRFSecret()
+				So(err, ShouldBeNil)
+				So((&Session{CSRFSecret: other}).VerifyCSRFToken(first), ShouldBeFalse)
+			})
+		})
+
+		Convey("When an invalid token is verified", func() {
+
+			Convey("Then it is rejected", func() {
+				So(s.VerifyCSRFToken(""), ShouldBeFalse)
+				So(s.VerifyCSRFToken("not-a-token"), ShouldBeFalse)
+				So(s.VerifyCSRFToken(secret), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given a session without a CSRF secret", t, func() {
+		s := &Session{ID: "1234"}
+
+		Convey("When a token is issued", func() {
+			token, err := s.CSRFToken()
+
+			Convey("Then the no secret error is returned", func() {
+				So(token, ShouldBeEmpty)
+				So(err, ShouldEqual, ErrNoCSRFSecret)
+				So(s.VerifyCSRFToken(token), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestClient_CSRF(t *testing.T) {
+	Convey("Given a client with CSRF enabled", t, func() {
+		fake := newFakeRedis()
+		client := newFakeClient(fake, Config{CSRF: true})
+
+		s := &Session{ID: "1234", Email: "user@email.com", Start: fake.Now()}
+		So(client.SetSession(s), ShouldBeNil)
+
+		Convey("When a session is stored without a CSRF secret", func() {
+
+			Convey("Then one is generated and stored with it", func() {
+				So(s.CSRFSecret, ShouldNotBeEmpty)
+
+				got, err := client.GetByID("1234")
+				So(err, ShouldBeNil)
+				So(got.CSRFSecret, ShouldEqual, s.CSRFSecret)
+			})
+		})
+
+		Convey("When a token issued for the session is verified against the stored session", func() {
+			token, err := s.CSRFToken()
+			So(err, ShouldBeNil)
+			got, err := client.GetByEmail("user@email.com")
+			So(err, ShouldBeNil)
+
+			Convey("Then it is valid", func() {
+				So(got.VerifyCSRFToken(token), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the session ID is rotated", func() {
+			token, err := s.CSRFToken()
+			So(err, ShouldBeNil)
+
+			rotated, err := client.RotateID("1234", "5678")
+			So(err, ShouldBeNil)
+
+			Convey("Then the session is only found by its new ID", func() {
+				So(rotated.ID, ShouldEqual, "5678")
+				So(rotated.Email, ShouldEqual, "user@email.com")
+				So(rotated.ExpiresAt, ShouldEqual, fake.Now().Add(testTTL))
+
+				_, err := client.GetByID("1234")
+				So(err, ShouldEqual, redis.Nil)
+
+				byEmail, err := client.GetByEmail("user@email.com")
+				So(err, ShouldBeNil)
+				So(byEmail.ID, ShouldEqual, "5678")
+			})
+
+			Convey("And its CSRF secret was rotated", func() {
+				So(rotated.CSRFSecret, ShouldNotBeEmpty)
+				So(rotated.CSRFSecret, ShouldNotEqual, s.CSRFSecret)
+				So(rotated.VerifyCSRFToken(token), ShouldBeFalse)
+
+				got, err := client.GetByID("5678")
+				So(err, ShouldBeNil)
+				So(got.CSRFSecret, ShouldEqual, rotated.CSRFSecret)
+			})
+		})
+
+		Convey("When a session that does not exist is rotated", func() {
+			_, err := client.RotateID("unknown", "5678")
+
+			Convey("Then redis.Nil is returned", func() {
+				So(err, ShouldEqual, redis.Nil)
+			})
+		})
+
+		Convey("When a session is rotated to its own ID", func() {
+			_, err := client.RotateID("1234", "1234")
+
+			Convey("Then the same session ID error is returned and the session is left alone", func() {
+				So(err, ShouldEqual, ErrSameSessionID)
+				got, err := client.GetByID("1234")
+				So(err, ShouldBeNil)
+				So(got.CSRFSecret, ShouldEqual, s.CSRFSecret)
+			})
+		})
+
+		Convey("When a session is rotated to the ID of another session", func() {
+			other := &Session{ID: "5678", Email: "other@email.com", Start: fake.Now()}
+			So(client.SetSession(other), ShouldBeNil)
+			_, err := client.RotateID("1234", "5678")
+
+			Convey("Then the session ID in use error is returned and neither session is changed", func() {
+				So(err, ShouldEqual, ErrSessionIDInUse)
+				got, err := client.GetByID("5678")
+				So(err, ShouldBeNil)
+				So(got.Email, ShouldEqual, "other@email.com")
+				got, err = client.GetByID("1234")
+				So(err, ShouldBeNil)
+				So(got.Email, ShouldEqual, "user@email.com")
+			})
+		})
+
+		Convey("When a session is rotated without a new ID", func() {
+			_, err := client.RotateID("1234", "")
+
+			Convey("Then the empty session ID error is returned", func() {
+				So(err, ShouldEqual, ErrEmptySessionID)
+			})
+		})
+	})
+
+	Convey("Given a memory store with CSRF enabled", t, func() {
+		store, err := NewMemoryStore(Config{TTL: testTTL, CSRF: true})
+		So(err, ShouldBeNil)
+
+		Convey("When a session is stored without a CSRF secret", func() {
+			s := &Session{ID: "1234", Email: "user@email.com"}
+			So(store.SetSessionContext(context.Background(), s), ShouldBeNil)
+
+			Convey("Then one is generated and stored with it", func() {
+				So(s.CSRFSecret, ShouldNotBeEmpty)
+
+				got, err := store.GetByIDContext(context.Background(), "1234")
+				So(err, ShouldBeNil)
+				So(got.CSRFSecret, ShouldEqual, s.CSRFSecret)
+			})
+		})
+	})
+
+	Convey("Given a client without CSRF enabled", t, func() {
+		fake := newFakeRedis()
+		client := newFakeClient(fake, Config{})
+
+		Convey("When a session is stored and rotated", func() {
+			s := &Session{ID: "1234", Email: "user@email.com"}
+			So(client.SetSession(s), ShouldBeNil)
+			rotated, err := client.RotateID("1234", "5678")
+			So(err, ShouldBeNil)
+
+			Convey("Then it has no CSRF secret", func() {
+				So(s.CSRFSecret, ShouldBeEmpty)
+				So(rotated.CSRFSecret, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,128 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// eventsChannel is the redis pub/sub channel that session lifecycle events are published on
+const eventsChannel = "sessions:events"
+
+// EventType - the kind of change to a session that an Event describes
+type EventType string
+
+// Possible values for EventType
+const (
+	SessionCreated   EventType = "created"
+	SessionRefreshed EventType = "refreshed"
+	SessionExpired   EventType = "expired"
+	SessionRevoked   EventType = "revoked"
+)
+
+// Event - a change to a session. Events only identify the session by ID so that no personal data is broadcast.
+type Event struct {
+	Type      EventType `json:"type"`
+	SessionID string    `json:"id"`
+}
+
+// Subscribe - returns a channel of the session events published by every client connected to the same redis instance
+// with events enabled. SessionExpired events are published when PruneIndex removes an expired session from the
+// expiry index. The subscription is closed, and the channel with it, when ctx is done.
+func (c *Client) Subscribe(ctx context.Context) (<-chan Event, error) {
+	pubSub := c.client.Subscribe(eventsChannel)
+
+	// Wait for confirmation so that no events are missed once Subscribe has returned
+	if _, err := pubSub.Receive(); err != nil {
+		pubSub.Close()
+		return nil, fmt.Errorf("redis client.Subscribe returned an unexpected error: %w", err)
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer pubSub.Close()
+		forwardEvents(ctx, pubSub.Channel(), events)
+	}()
+
+	return events, nil
+}
+
+// forwardEvents decodes the messages received on msgs and sends them to events until ctx is done or msgs is closed,
+// then closes events. Messages that are not valid events are dropped.
+func forwardEvents(ctx context.Context, msgs <-chan *redis.Message, events chan<- Event) {
+	defer close(events)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+
+			var e Event
+			if err := json.Unmarshal([]byte(msg.Payload), &e); err != nil || e.Type == "" {
+				continue
+			}
+
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// eventsChannel returns the channel to publish events on, or an empty string if events are disabled
+func (c *Client) eventsChannel() string {
+	if !c.events {
+		return ""
+	}
+	return eventsChannel
+}
+
+// publish sends an event for the session with the provided ID if events are enabled
+func (c *Client) publish(t EventType, id string) error {
+	if !c.events {
+		return nil
+	}
+
+	payload, err := json.Marshal(Event{Type: t, SessionID: id})
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Publish(eventsChannel, payload).Err()
+	if err != nil {
+		return fmt.Errorf("redis client.Publish returned an unexpected error: %w", err)
+	}
+
+	return nil
+}
+
+// publishEvents sends events for a change that has already been made, if events are enabled. Publishing is not
+// retried, as subscribers would see the event twice, and a failure is wrapped in ErrEventNotPublished so that callers
+// can tell the change itself was made.
+func (c *Client) publishEvents(ctx context.Context, events ...Event) error {
+	if !c.events {
+		return nil
+	}
+
+	err := c.do(ctx, false, func() error {
+		for _, e := range events {
+			if err := c.publish(e.Type, e.SessionID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrEventNotPublished, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Events(t *testing.T) {
+	Convey("Given a client with events enabled", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusResult("OK", nil),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		client.events = true
+		mockRedisClient.PublishFunc = func(channel string, message interface{}) *redis.IntCmd {
+			return redis.NewIntResult(1, nil)
+		}
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+		}
+
+		Convey("When a session is set", func() {
+			err := client.SetSession(&Session{ID: "1234", Email: "user@email.com"})
+			So(err, ShouldBeNil)
+
+			Convey("Then a created event is published without the session email", func() {
+				So(mockRedisClient.PublishCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.PublishCalls()[0].Channel, ShouldEqual, eventsChannel)
+				So(string(mockRedisClient.PublishCalls()[0].Message.([]byte)), ShouldEqual, `{"type":"created","id":"1234"}`)
+			})
+		})
+
+		Convey("When a session is read", func() {
+			_, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the refresh script is asked to publish a refreshed event", func() {
+				So(mockRedisClient.EvalShaCalls()[0].Args[2], ShouldEqual, eventsChannel)
+				So(mockRedisClient.PublishCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When publishing events fails", func() {
+			mockRedisClient.PublishFunc = func(channel string, message interface{}) *redis.IntCmd {
+				if channel == eventsChannel {
+					return redis.NewIntResult(0, errors.New("some redis error"))
+				}
+				return redis.NewIntResult(0, nil)
+			}
+
+			Convey("Then a session that is set is stored, and the error says only the event was not published", func() {
+				err := client.SetSession(&Session{ID: "1234", Email: "user@email.com"})
+				So(errors.Is(err, ErrEventNotPublished), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "session event not published: redis client.Publish returned an unexpected error: some redis error")
+				So(mockRedisClient.SetCalls(), ShouldHaveLength, 2)
+			})
+
+			Convey("Then a session that is deleted is removed, and the error says only the event was not published", func() {
+				err := client.DeleteByID("1234")
+				So(errors.Is(err, ErrEventNotPublished), ShouldBeTrue)
+				So(scriptKeys(mockRedisClient, deleteScript), ShouldHaveLength, 1)
+			})
+
+			Convey("Then a session whose ID is rotated is returned, and the error says only the event was not published", func() {
+				mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+					if sha1 == rotateScript.Hash() {
+						return redis.NewCmdResult(int64(1), nil)
+					}
+					return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+				}
+				s, err := client.RotateID("1234", "5678")
+				So(errors.Is(err, ErrEventNotPublished), ShouldBeTrue)
+				So(s.ID, ShouldEqual, "5678")
+				So(scriptKeys(mockRedisClient, rotateScript), ShouldResemble, [][]string{{"5678", "user@email.com", expiryIndexKey, "1234"}})
+			})
+		})
+	})
+
+	Convey("Given a client with events disabled", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusResult("OK", nil),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+
+		Convey("When a session is set", func() {
+			err := client.SetSession(&Session{ID: "1234", Email: "user@email.com"})
+
+			Convey("Then no event is published", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.PublishCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestForwardEvents(t *testing.T) {
+	Convey("Given a stream of pub/sub messages", t, func() {
+		msgs := make(chan *redis.Message, 3)
+		revoked, _ := json.Marshal(Event{Type: SessionRevoked, SessionID: "1234"})
+		msgs <- &redis.Message{Channel: eventsChannel, Payload: "not an event"}
+		msgs <- &redis.Message{Channel: eventsChannel, Payload: string(revoked)}
+		msgs <- &redis.Message{Channel: eventsChannel, Payload: `{"type":"expired","id":"5678"}`}
+		close(msgs)
+
+		Convey("When the messages are forwarded", func() {
+			events := make(chan Event)
+			go forwardEvents(context.Background(), msgs, events)
+
+			var received []Event
+			for e := range events {
+				received = append(received, e)
+			}
+
+			Convey("Then valid events are decoded and invalid messages are dropped", func() {
+				So(received, ShouldResemble, []Event{
+					{Type: SessionRevoked, SessionID: "1234"},
+					{Type: SessionExpired, SessionID: "5678"},
+				})
+			})
+		})
+	})
+
+	Convey("Given the context is cancelled", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		events := make(chan Event)
+		go forwardEvents(ctx, make(chan *redis.Message), events)
+
+		Convey("When the context is cancelled", func() {
+			cancel()
+
+			Convey("Then the events channel is closed", func() {
+				select {
+				case _, ok := <-events:
+					So(ok, ShouldBeFalse)
+				case <-time.After(time.Second):
+					So("events channel was not closed", ShouldBeEmpty)
+				}
+			})
+		})
+	})
+}
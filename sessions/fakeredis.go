@@ -0,0 +1,70 @@
+package sessions
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+// FakeRedis - a RedisClienter for tests, connected to an in-process miniredis server with a clock that only moves when
+// told to. miniredis runs the client's lua scripts and keys expire as the clock passes their TTL, so session flows
+// including expiry can be tested without redis. Use it with NewClientWithRedisClienter, passing FakeRedis.Now as
+// Config.Clock so the client shares its clock. Several clients can share a FakeRedis, but closing any of them closes
+// it for all.
+type FakeRedis struct {
+	redisClient
+
+	server    *miniredis.Miniredis
+	mu        sync.Mutex
+	now       time.Time
+	closeOnce sync.Once
+}
+
+var _ RedisClienter = (*FakeRedis)(nil)
+
+// NewFakeRedis - returns an empty FakeRedis with its clock set to the current time. It panics if the server cannot
+// listen on a local port.
+func NewFakeRedis() *FakeRedis {
+	server, err := miniredis.Run()
+	if err != nil {
+		panic(fmt.Sprintf("failed to start fake redis: %v", err))
+	}
+
+	return &FakeRedis{
+		redisClient: redisClient{redis.NewClient(&redis.Options{Addr: server.Addr()})},
+		server:      server,
+		now:         time.Now(),
+	}
+}
+
+// Now - returns the current time of the fake clock
+func (f *FakeRedis) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Advance - moves the fake clock forward by d, expiring any keys whose TTL has passed
+func (f *FakeRedis) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	f.server.FastForward(d)
+}
+
+// Close - closes the connection and stops the server, after which every call fails as it would with a closed redis
+// client
+func (f *FakeRedis) Close() error {
+	var err error
+	f.closeOnce.Do(func() {
+		err = f.redisClient.Close()
+		f.server.Close()
+	})
+
+	return err
+}
@@ -0,0 +1,280 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newFakeClient returns a client using fake with the provided config options and the fake's clock
+func newFakeClient(fake *FakeRedis, cfg Config) *Client {
+	cfg.TTL = testTTL
+	cfg.Clock = fake.Now
+
+	c, err := NewClientWithRedisClienter(cfg, fake)
+	So(err, ShouldBeNil)
+
+	return c
+}
+
+// newFakeRedis returns a fake redis that is closed when the current convey scope is reset
+func newFakeRedis() *FakeRedis {
+	fake := NewFakeRedis()
+	Reset(func() { fake.Close() })
+
+	return fake
+}
+
+func TestNewClientWithRedisClienter(t *testing.T) {
+	Convey("Given an invalid TTL", t, func() {
+
+		Convey("When the client is created", func() {
+			c, err := NewClientWithRedisClienter(Config{}, newFakeRedis())
+
+			Convey("Then the client will not be created and the invalid TTL error is returned", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldEqual, ErrInvalidTTL)
+			})
+		})
+	})
+}
+
+func TestFakeRedis(t *testing.T) {
+	Convey("Given a fake redis", t, func() {
+		fake := newFakeRedis()
+
+		Convey("When a key is set with an expiry", func() {
+			So(fake.Set("key", []byte("value"), time.Minute).Err(), ShouldBeNil)
+
+			Convey("Then it can be read with its remaining TTL", func() {
+				So(fake.Get("key").Val(), ShouldEqual, "value")
+				So(fake.PTTL("key").Val(), ShouldEqual, time.Minute)
+			})
+
+			Convey("And it expires once the clock passes its TTL", func() {
+				fake.Advance(time.Minute)
+				So(fake.Get("key").Err(), ShouldEqual, redis.Nil)
+				So(fake.PTTL("key").Val(), ShouldEqual, keyNotFoundTTL)
+			})
+
+			Convey("And its TTL can be extended", func() {
+				fake.Advance(30 * time.Second)
+				So(fake.Expire("key", time.Minute).Val(), ShouldBeTrue)
+				fake.Advance(45 * time.Second)
+				So(fake.Get("key").Val(), ShouldEqual, "value")
+			})
+		})
+
+		Convey("When a key is set without an expiry", func() {
+			fake.Set("key", "value", 0)
+
+			Convey("Then its TTL is -1ms as with redis", func() {
+				So(fake.PTTL("key").Val(), ShouldEqual, -time.Millisecond)
+			})
+		})
+
+		Convey("When keys are scanned", func() {
+			fake.Set("a", "1", 0)
+			fake.Set("b", "2", 0)
+			fake.Set("c", "3", 0)
+
+			keys, cursor := fake.Scan(0, "*", 2).Val()
+
+			Convey("Then every key is returned once and the cursor ends at zero", func() {
+				So(keys, ShouldHaveLength, 3)
+				So(keys, ShouldContain, "a")
+				So(keys, ShouldContain, "b")
+				So(keys, ShouldContain, "c")
+				So(cursor, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When members are added to a sorted set", func() {
+			fake.ZAdd("index", redis.Z{Score: 1, Member: "a"}, redis.Z{Score: 2, Member: "b"}, redis.Z{Score: 3, Member: "c"})
+
+			Convey("Then they can be counted by score", func() {
+				So(fake.ZCount("index", "2", "+inf").Val(), ShouldEqual, 2)
+				So(fake.ZCount("index", "(2", "+inf").Val(), ShouldEqual, 1)
+				So(fake.ZCount("index", "-inf", "(3").Val(), ShouldEqual, 2)
+			})
+
+			Convey("And reading it as a string is an error", func() {
+				So(fake.Get("index").Err(), ShouldBeError)
+				So(fake.Get("index").Err().Error(), ShouldStartWith, "WRONGTYPE")
+			})
+		})
+
+		Convey("When the fake is closed", func() {
+			So(fake.Close(), ShouldBeNil)
+
+			Convey("Then calls fail as they would with a closed client", func() {
+				So(fake.Ping().Err(), ShouldBeError, "redis: client is closed")
+				So(isConnectionError(fake.Ping().Err()), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestFakeRedis_Client(t *testing.T) {
+	Convey("Given a client using a fake redis", t, func() {
+		fake := newFakeRedis()
+		client := newFakeClient(fake, Config{Events: true})
+
+		s := &Session{ID: "1234", Email: "user@email.com", Start: fake.Now()}
+		So(client.SetSession(s), ShouldBeNil)
+
+		Convey("When the session is read by ID and email", func() {
+			byID, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+			byEmail, err := client.GetByEmail("user@email.com")
+			So(err, ShouldBeNil)
+
+			Convey("Then the same session is returned with the fake clock", func() {
+				So(byID.ID, ShouldEqual, "1234")
+				So(byEmail.ID, ShouldEqual, "1234")
+				So(byID.LastAccessed, ShouldEqual, fake.Now())
+				So(byID.ExpiresAt, ShouldEqual, fake.Now().Add(testTTL))
+			})
+		})
+
+		Convey("When the clock passes the TTL", func() {
+			fake.Advance(testTTL)
+
+			Convey("Then the session has expired", func() {
+				_, err := client.GetByID("1234")
+				So(err, ShouldEqual, redis.Nil)
+				_, err = client.GetByEmail("user@email.com")
+				So(err, ShouldEqual, redis.Nil)
+			})
+
+			Convey("And pruning the index reports it as expired", func() {
+				removed, err := client.PruneIndex(context.Background())
+				So(err, ShouldBeNil)
+				So(removed, ShouldResemble, []string{"1234"})
+
+				stats, err := client.Stats(context.Background())
+				So(err, ShouldBeNil)
+				So(stats.ActiveSessions, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the session is read shortly before it would expire", func() {
+			fake.Advance(testTTL - time.Minute)
+			_, err := client.GetByEmail("user@email.com")
+			So(err, ShouldBeNil)
+			fake.Advance(2 * time.Minute)
+
+			Convey("Then both of its keys were refreshed", func() {
+				_, ttl, err := client.PeekByID("1234")
+				So(err, ShouldBeNil)
+				So(ttl, ShouldEqual, testTTL-2*time.Minute)
+				_, _, err = client.PeekByEmail("user@email.com")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When the session is deleted", func() {
+			So(client.DeleteByID("1234"), ShouldBeNil)
+
+			Convey("Then neither of its keys remain", func() {
+				So(fake.Get("1234").Err(), ShouldEqual, redis.Nil)
+				So(fake.Get("user@email.com").Err(), ShouldEqual, redis.Nil)
+
+				stats, err := client.Stats(context.Background())
+				So(err, ShouldBeNil)
+				So(stats.ActiveSessions, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When events are subscribed to", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events, err := client.Subscribe(ctx)
+			So(err, ShouldBeNil)
+
+			So(client.DeleteByID("1234"), ShouldBeNil)
+
+			Convey("Then events published by the client are received", func() {
+				So(<-events, ShouldResemble, Event{Type: SessionRevoked, SessionID: "1234"})
+			})
+		})
+
+		Convey("When more sessions than fit in one scan batch are revoked", func() {
+			for i := 0; i < 3*scanBatchSize; i++ {
+				id := fmt.Sprintf("id-%d", i)
+				So(client.SetSession(&Session{ID: id, Email: id + "@email.com", Start: fake.Now()}), ShouldBeNil)
+			}
+
+			revoked, err := client.RevokeWhere(context.Background(), func(*Session) bool { return true })
+
+			Convey("Then every session is revoked", func() {
+				So(err, ShouldBeNil)
+				So(revoked, ShouldEqual, 3*scanBatchSize+1)
+
+				stats, err := client.Stats(context.Background())
+				So(err, ShouldBeNil)
+				So(stats.ActiveSessions, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When events are subscribed to but not read", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			_, err := client.Subscribe(ctx)
+			So(err, ShouldBeNil)
+
+			done := make(chan error, 1)
+			go func() {
+				for i := 0; i < 200; i++ {
+					id := fmt.Sprintf("id-%d", i)
+					if err := client.SetSession(&Session{ID: id, Email: id + "@email.com", Start: fake.Now()}); err != nil {
+						done <- err
+						return
+					}
+				}
+				done <- nil
+			}()
+
+			Convey("Then publishing does not block the client", func() {
+				select {
+				case err := <-done:
+					So(err, ShouldBeNil)
+				case <-time.After(5 * time.Second):
+					So("sessions were not stored before the timeout", ShouldBeEmpty)
+				}
+			})
+		})
+
+		Convey("When another client with a local cache shares the fake", func() {
+			cache := NewLRUCache(10, time.Minute)
+			other := newFakeClient(fake, Config{LocalCache: cache})
+			defer other.Close()
+
+			_, err := other.GetByID("1234")
+			So(err, ShouldBeNil)
+			So(cache.Stats().Size, ShouldEqual, 1)
+
+			So(client.DeleteByID("1234"), ShouldBeNil)
+
+			Convey("Then the revocation removes the session from its cache", func() {
+				So(waitFor(func() bool { return cache.Stats().Size == 0 }), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+// waitFor polls cond until it is true or a second has passed
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return cond()
+}
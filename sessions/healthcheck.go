@@ -2,15 +2,24 @@ package sessions
 
 import (
 	"context"
+	"errors"
+
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
 )
 
-const HealthyMessage = "redis is OK"
+const (
+	HealthyMessage  = "redis is OK"
+	HalfOpenMessage = "redis circuit breaker is half-open, probing for recovery"
+)
 
 func (c *Client) Checker(ctx context.Context, state *health.CheckState) error {
-	err := c.Ping()
+	err := c.PingContext(ctx)
 	if err != nil {
-		// Generic error
+		// Another call is already probing redis for recovery
+		if errors.Is(err, ErrUnavailable) && c.CircuitState() == CircuitHalfOpen {
+			return state.Update(health.StatusWarning, HalfOpenMessage, 0)
+		}
+		// Generic error, or circuit breaker open
 		return state.Update(health.StatusCritical, err.Error(), 0)
 	}
 	// Success
@@ -0,0 +1,277 @@
+package sessions_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// redisServerEnv names the environment variable holding the path of a redis-server binary to run the integration
+// tests against. If it is not set they run against miniredis, which runs the client's lua scripts with its own lua
+// interpreter rather than the one in redis.
+const redisServerEnv = "SESSIONS_REDIS_SERVER"
+
+const (
+	integrationPassword = "integration"
+	integrationTTL      = 2 * time.Second
+)
+
+// integrationTarget is a redis server for the integration tests, with a way to move its clock forward
+type integrationTarget struct {
+	addr    string
+	clock   func() time.Time
+	advance func(d time.Duration)
+}
+
+// newIntegrationTarget starts the server selected by redisServerEnv, which is stopped when the test ends
+func newIntegrationTarget(t testing.TB) *integrationTarget {
+	bin := os.Getenv(redisServerEnv)
+	if bin == "" {
+		m, err := miniredis.Run()
+		if err != nil {
+			t.Fatalf("failed to start miniredis: %v", err)
+		}
+		t.Cleanup(m.Close)
+		m.RequireAuth(integrationPassword)
+
+		// Keys in miniredis only expire when it is fast forwarded, so the client's clock is moved with it
+		var mu sync.Mutex
+		now := time.Now()
+		clock := func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		}
+		advance := func(d time.Duration) {
+			mu.Lock()
+			now = now.Add(d)
+			mu.Unlock()
+			m.FastForward(d)
+		}
+
+		return &integrationTarget{addr: m.Addr(), clock: clock, advance: advance}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	l.Close()
+
+	cmd := exec.Command(bin, "--bind", "127.0.0.1", "--port", port, "--requirepass", integrationPassword, "--save", "", "--appendonly", "no")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start %s: %v", bin, err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	target := &integrationTarget{addr: "127.0.0.1:" + port, advance: time.Sleep}
+
+	rc := target.rawClient()
+	defer rc.Close()
+	for deadline := time.Now().Add(5 * time.Second); rc.Ping().Err() != nil; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s did not start listening on port %s", bin, port)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return target
+}
+
+// client returns a sessions client for the target, which is closed when the test ends
+func (it *integrationTarget) client(t testing.TB) *sessions.Client {
+	c, err := sessions.NewClient(sessions.Config{
+		Addr:     it.addr,
+		Password: integrationPassword,
+		TTL:      integrationTTL,
+		Clock:    it.clock,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sessions client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+// rawClient returns a redis client for inspecting the keys written by the sessions client
+func (it *integrationTarget) rawClient() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: it.addr, Password: integrationPassword})
+}
+
+// now returns the target's current time
+func (it *integrationTarget) now() time.Time {
+	if it.clock != nil {
+		return it.clock()
+	}
+	return time.Now()
+}
+
+func TestIntegration(t *testing.T) {
+	Convey("Given a session stored in a redis server", t, func() {
+		target := newIntegrationTarget(t)
+		client := target.client(t)
+		rc := target.rawClient()
+		defer rc.Close()
+
+		// Truncate to the stored precision so the times round-trip exactly
+		start := target.now().Add(-time.Hour).Truncate(time.Millisecond)
+		s := &sessions.Session{ID: "1234", Email: "user@email.com", Start: start, LastAccessed: start}
+		So(client.SetSession(s), ShouldBeNil)
+
+		Convey("When its keys are read directly", func() {
+			byID, err := rc.Get(s.ID).Result()
+			So(err, ShouldBeNil)
+			byEmail, err := rc.Get(s.Email).Result()
+			So(err, ShouldBeNil)
+
+			Convey("Then both hold the same payload and TTL", func() {
+				So(byEmail, ShouldEqual, byID)
+				So(rc.PTTL(s.ID).Val(), ShouldBeGreaterThan, integrationTTL-time.Second)
+				So(rc.PTTL(s.Email).Val(), ShouldBeGreaterThan, integrationTTL-time.Second)
+			})
+		})
+
+		Convey("When it is read back", func() {
+			got, _, err := client.PeekByID(s.ID)
+			So(err, ShouldBeNil)
+
+			Convey("Then its times are returned in UTC", func() {
+				So(got.Start, ShouldEqual, start.UTC())
+				So(got.LastAccessed, ShouldEqual, start.UTC())
+			})
+		})
+
+		Convey("When its TTL passes", func() {
+			target.advance(integrationTTL + 100*time.Millisecond)
+
+			Convey("Then it has expired under both keys", func() {
+				_, err := client.GetByID(s.ID)
+				So(err, ShouldEqual, redis.Nil)
+				_, err = client.GetByEmail(s.Email)
+				So(err, ShouldEqual, redis.Nil)
+				So(rc.Exists(s.ID, s.Email).Val(), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When it is read after half of its TTL", func() {
+			target.advance(integrationTTL / 2)
+			_, err := client.GetByEmail(s.Email)
+			So(err, ShouldBeNil)
+			target.advance(integrationTTL/2 + 100*time.Millisecond)
+
+			Convey("Then both keys were refreshed with the same payload", func() {
+				byID, err := rc.Get(s.ID).Result()
+				So(err, ShouldBeNil)
+				byEmail, err := rc.Get(s.Email).Result()
+				So(err, ShouldBeNil)
+				So(byEmail, ShouldEqual, byID)
+			})
+
+			Convey("And the time it was accessed was stored", func() {
+				got, _, err := client.PeekByID(s.ID)
+				So(err, ShouldBeNil)
+				So(got.LastAccessed, ShouldHappenAfter, start)
+				So(got.Start, ShouldEqual, start.UTC())
+			})
+		})
+
+		Convey("When a newer session is stored for the same email and the older one is read", func() {
+			target.advance(integrationTTL / 2)
+			newer := &sessions.Session{ID: "5678", Email: s.Email, Start: target.now(), LastAccessed: target.now()}
+			So(client.SetSession(newer), ShouldBeNil)
+			newerJSON, err := rc.Get(s.Email).Result()
+			So(err, ShouldBeNil)
+
+			_, err = client.GetByID(s.ID)
+			So(err, ShouldBeNil)
+
+			Convey("Then the email key still belongs to the newer session", func() {
+				So(rc.Get(s.Email).Val(), ShouldEqual, newerJSON)
+				got, err := client.GetByEmail(s.Email)
+				So(err, ShouldBeNil)
+				So(got.ID, ShouldEqual, newer.ID)
+			})
+		})
+
+		Convey("When it is deleted", func() {
+			So(client.DeleteByID(s.ID), ShouldBeNil)
+
+			Convey("Then neither of its keys remain", func() {
+				So(rc.Exists(s.ID, s.Email).Val(), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestIntegration_Concurrency(t *testing.T) {
+	Convey("Given clients sharing a redis server", t, func() {
+		target := newIntegrationTarget(t)
+		clients := []*sessions.Client{target.client(t), target.client(t)}
+
+		Convey("When sessions are stored, read and deleted concurrently", func() {
+			var wg sync.WaitGroup
+			errs := make(chan error, 100)
+			for w := 0; w < 8; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					client := clients[w%len(clients)]
+					for i := 0; i < 25; i++ {
+						id := fmt.Sprintf("session-%d", i%5)
+						s := &sessions.Session{ID: id, Email: id + "@email.com", Start: target.now(), LastAccessed: target.now()}
+
+						var err error
+						switch (w + i) % 3 {
+						case 0:
+							err = client.SetSession(s)
+						case 1:
+							var got *sessions.Session
+							got, err = client.GetByID(id)
+							if err == nil && got.Email != s.Email {
+								err = fmt.Errorf("session %s returned for email %s", got.ID, got.Email)
+							}
+						case 2:
+							err = client.DeleteByID(id)
+						}
+						if err != nil && err != redis.Nil {
+							errs <- err
+							return
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+			close(errs)
+
+			Convey("Then no operation fails", func() {
+				for err := range errs {
+					So(err, ShouldBeNil)
+				}
+			})
+
+			Convey("And a session stored afterwards is consistent under both keys", func() {
+				s := &sessions.Session{ID: "session-0", Email: "session-0@email.com", Start: target.now(), LastAccessed: target.now()}
+				So(clients[0].SetSession(s), ShouldBeNil)
+
+				byEmail, err := clients[1].GetByEmail(s.Email)
+				So(err, ShouldBeNil)
+				So(byEmail.ID, ShouldEqual, s.ID)
+			})
+		})
+	})
+}
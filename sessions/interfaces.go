@@ -3,16 +3,61 @@ package sessions
 //go:generate moq -out mock_redisclienter.go . RedisClienter
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-redis/redis"
 )
 
+// LocalCache - interface for an in-process cache of sessions keyed by session ID
+type LocalCache interface {
+	Remove(id string)
+	Purge()
+}
+
+// PubSub - interface for a redis pub/sub subscription, as returned by RedisClienter.Subscribe
+type PubSub interface {
+	Receive() (interface{}, error)
+	Channel() <-chan *redis.Message
+	Close() error
+}
+
 // RedisClienter - interface for redis
 type RedisClienter interface {
 	Set(key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 	Get(key string) *redis.StringCmd
 	Expire(key string, expiration time.Duration) *redis.BoolCmd
+	PTTL(key string) *redis.DurationCmd
+	Eval(script string, keys []string, args ...interface{}) *redis.Cmd
+	EvalSha(sha1 string, keys []string, args ...interface{}) *redis.Cmd
+	ScriptExists(hashes ...string) *redis.BoolSliceCmd
+	ScriptLoad(script string) *redis.StringCmd
+	Del(keys ...string) *redis.IntCmd
+	Scan(cursor uint64, match string, count int64) *redis.ScanCmd
+	ZAdd(key string, members ...redis.Z) *redis.IntCmd
+	ZRem(key string, members ...interface{}) *redis.IntCmd
+	ZCount(key, min, max string) *redis.IntCmd
+	Publish(channel string, message interface{}) *redis.IntCmd
+	Subscribe(channels ...string) PubSub
 	FlushAll() *redis.StatusCmd
 	Ping() *redis.StatusCmd
+	PoolStats() *redis.PoolStats
+	Close() error
+}
+
+// SessionStore - interface for a backend that stores sessions, implemented by Client with redis and by MemoryStore in
+// memory. Missing sessions are reported with ErrSessionNotFound by every implementation, so stores can be swapped or
+// wrapped in decorators, for caching, metrics or encryption, without changing how callers handle them.
+type SessionStore interface {
+	SetSessionContext(ctx context.Context, s *Session) error
+	GetByIDContext(ctx context.Context, id string) (*Session, error)
+	GetByEmailContext(ctx context.Context, email string) (*Session, error)
+	PeekByIDContext(ctx context.Context, id string) (*Session, time.Duration, error)
+	PeekByEmailContext(ctx context.Context, email string) (*Session, time.Duration, error)
+	TTLContext(ctx context.Context, id string) (time.Duration, error)
+	SessionTTL() time.Duration
+	DeleteByIDContext(ctx context.Context, id string) error
+	DeleteAllContext(ctx context.Context) error
+	PingContext(ctx context.Context) error
+	Close() error
 }
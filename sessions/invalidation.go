@@ -0,0 +1,62 @@
+package sessions
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	// invalidationsChannel is the redis pub/sub channel that session revocations are broadcast on
+	invalidationsChannel = "sessions:invalidations"
+
+	// invalidateAll is broadcast in place of a session ID when every session has been removed
+	invalidateAll = "*"
+)
+
+// listenForInvalidations subscribes to revocations broadcast by every client connected to the same redis instance and
+// removes the revoked sessions from the local cache until the client is closed
+func (c *Client) listenForInvalidations() error {
+	pubSub := c.client.Subscribe(invalidationsChannel)
+
+	// Wait for confirmation so that no revocations are missed once the client has been created
+	if _, err := pubSub.Receive(); err != nil {
+		pubSub.Close()
+		return fmt.Errorf("redis client.Subscribe returned an unexpected error: %w", err)
+	}
+	c.closeFn = pubSub.Close
+
+	go applyInvalidations(c.localCache, pubSub.Channel())
+
+	return nil
+}
+
+// applyInvalidations removes the session IDs received on msgs from cache until msgs is closed
+func applyInvalidations(cache LocalCache, msgs <-chan *redis.Message) {
+	for msg := range msgs {
+		if msg.Payload == invalidateAll {
+			cache.Purge()
+			continue
+		}
+		cache.Remove(msg.Payload)
+	}
+}
+
+// invalidate removes the session with the provided ID from the local cache, if there is one, and broadcasts the
+// revocation so that other clients do the same
+func (c *Client) invalidate(id string) error {
+	if c.localCache != nil {
+		if id == invalidateAll {
+			c.localCache.Purge()
+		} else {
+			c.localCache.Remove(id)
+		}
+	}
+
+	err := c.client.Publish(invalidationsChannel, id).Err()
+	if err != nil {
+		return fmt.Errorf("redis client.Publish returned an unexpected error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeLocalCache records the calls made to it by the client
+type fakeLocalCache struct {
+	removed []string
+	purged  int
+}
+
+func (f *fakeLocalCache) Remove(id string) {
+	f.removed = append(f.removed, id)
+}
+
+func (f *fakeLocalCache) Purge() {
+	f.purged++
+}
+
+// stubPubSub is a subscription whose confirmation fails with receiveErr and whose Close fails with closeErr
+type stubPubSub struct {
+	receiveErr error
+	closeErr   error
+	closed     bool
+}
+
+func (s *stubPubSub) Receive() (interface{}, error) {
+	if s.receiveErr != nil {
+		return nil, s.receiveErr
+	}
+	return &redis.Subscription{Kind: "subscribe", Channel: invalidationsChannel, Count: 1}, nil
+}
+
+func (s *stubPubSub) Channel() <-chan *redis.Message {
+	return make(chan *redis.Message)
+}
+
+func (s *stubPubSub) Close() error {
+	s.closed = true
+	return s.closeErr
+}
+
+func TestNewClient_LocalCache(t *testing.T) {
+	Convey("Given redis does not confirm the subscription to revocations", t, func() {
+		pubSub := &stubPubSub{receiveErr: errors.New("connection refused")}
+		mockRedisClient := &RedisClienterMock{
+			SubscribeFunc: func(channels ...string) PubSub { return pubSub },
+		}
+
+		Convey("When a client with a local cache is created", func() {
+			c, err := NewClientWithRedisClienter(Config{TTL: testTTL, LocalCache: &fakeLocalCache{}}, mockRedisClient)
+
+			Convey("Then the subscription error is returned and the subscription is closed", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "redis client.Subscribe returned an unexpected error: connection refused")
+				So(pubSub.closed, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a client with a local cache whose subscription and connection fail to close", t, func() {
+		pubSub := &stubPubSub{closeErr: errors.New("subscription already closed")}
+		connErr := errors.New("connection already closed")
+		mockRedisClient := &RedisClienterMock{
+			SubscribeFunc: func(channels ...string) PubSub { return pubSub },
+			CloseFunc:     func() error { return connErr },
+		}
+		c, err := NewClientWithRedisClienter(Config{TTL: testTTL, LocalCache: &fakeLocalCache{}}, mockRedisClient)
+		So(err, ShouldBeNil)
+		So(mockRedisClient.SubscribeCalls()[0].Channels, ShouldResemble, []string{invalidationsChannel})
+
+		Convey("When the client is closed", func() {
+			err := c.Close()
+
+			Convey("Then both errors are returned", func() {
+				So(pubSub.closed, ShouldBeTrue)
+				So(errors.Is(err, pubSub.closeErr), ShouldBeTrue)
+				So(errors.Is(err, connErr), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestClient_Invalidation(t *testing.T) {
+	Convey("Given a client with a local cache", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), int64(600000)}, nil)
+		}
+		cache := &fakeLocalCache{}
+		client.localCache = cache
+
+		Convey("When a session is deleted", func() {
+			err := client.DeleteByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the session is removed from the local cache", func() {
+				So(cache.removed, ShouldResemble, []string{"1234"})
+			})
+
+			Convey("And the revocation is broadcast to other clients", func() {
+				So(mockRedisClient.PublishCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.PublishCalls()[0].Channel, ShouldEqual, invalidationsChannel)
+				So(mockRedisClient.PublishCalls()[0].Message, ShouldEqual, "1234")
+			})
+		})
+
+		Convey("When all sessions are deleted", func() {
+			err := client.DeleteAll()
+			So(err, ShouldBeNil)
+
+			Convey("Then the local cache is purged", func() {
+				So(cache.purged, ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestApplyInvalidations(t *testing.T) {
+	Convey("Given revocations broadcast by other clients", t, func() {
+		msgs := make(chan *redis.Message, 3)
+		msgs <- &redis.Message{Channel: invalidationsChannel, Payload: "1234"}
+		msgs <- &redis.Message{Channel: invalidationsChannel, Payload: invalidateAll}
+		msgs <- &redis.Message{Channel: invalidationsChannel, Payload: "5678"}
+		close(msgs)
+
+		Convey("When they are applied to the local cache", func() {
+			cache := &fakeLocalCache{}
+			applyInvalidations(cache, msgs)
+
+			Convey("Then revoked sessions are removed and the cache is purged when all sessions are removed", func() {
+				So(cache.removed, ShouldResemble, []string{"1234", "5678"})
+				So(cache.purged, ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package sessions
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/ONSdigital/log.go/log"
+)
+
+// redactedKeyLength is the number of hex characters of the HMAC kept by Client.RedactKey
+const redactedKeyLength = 12
+
+// Logger - interface for a structured logger that the client reports slow operations, retries, failures and circuit
+// breaker transitions to. Session IDs and emails are only logged redacted with Client.RedactKey, and only if
+// Config.LogRedactionKey is set.
+type Logger interface {
+	Event(ctx context.Context, event string, data map[string]interface{}, err error)
+}
+
+// LoggerFunc - adapts a function to the Logger interface
+type LoggerFunc func(ctx context.Context, event string, data map[string]interface{}, err error)
+
+// Event - calls f(ctx, event, data, err)
+func (f LoggerFunc) Event(ctx context.Context, event string, data map[string]interface{}, err error) {
+	f(ctx, event, data, err)
+}
+
+// NewDPLogger - returns a Logger that writes events with dp-log, at error severity if there is an error
+func NewDPLogger() Logger {
+	return LoggerFunc(func(ctx context.Context, event string, data map[string]interface{}, err error) {
+		if err != nil {
+			log.Event(ctx, event, log.ERROR, log.Error(err), log.Data(data))
+			return
+		}
+		log.Event(ctx, event, log.INFO, log.Data(data))
+	})
+}
+
+// RedactKey - returns a short HMAC of a session ID or email keyed with Config.LogRedactionKey, as it appears in log
+// events, so that its events can be found. Without the key the HMAC cannot be computed from a guessed email. It returns
+// an empty string if no key is configured, as session IDs and emails are then left out of log events.
+func (c *Client) RedactKey(key string) string {
+	if len(c.logRedactionKey) == 0 {
+		return ""
+	}
+
+	h := hmac.New(sha256.New, c.logRedactionKey)
+	h.Write([]byte(key))
+	return hex.EncodeToString(h.Sum(nil))[:redactedKeyLength]
+}
+
+// log writes an event to the logger, if one is configured
+func (c *Client) log(ctx context.Context, event string, data map[string]interface{}, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger.Event(ctx, event, data, err)
+}
+
+// logOperation logs the outcome of an operation if it failed or was slow. ErrSessionNotFound is a miss rather than a
+// failure.
+func (c *Client) logOperation(ctx context.Context, o *operation, elapsed time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	data := o.logData()
+	data["duration"] = elapsed.String()
+
+	if err != nil && err != ErrSessionNotFound {
+		c.log(ctx, "session operation failed", data, err)
+		return
+	}
+
+	if c.isSlow(elapsed) {
+		c.log(ctx, "slow session operation", data, nil)
+	}
+}
+
+// logRetry logs that an operation is being retried after a transient error
+func (c *Client) logRetry(ctx context.Context, attempt int, wait time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	data := map[string]interface{}{}
+	if o, ok := ctx.Value(operationKey{}).(*operation); ok {
+		data = o.logData()
+	}
+	data["attempt"] = attempt
+	data["backoff"] = wait.String()
+
+	c.log(ctx, "retrying session operation", data, err)
+}
+
+// logTransition logs a change in the state of the circuit breaker
+func (c *Client) logTransition(from, to CircuitState) {
+	c.log(context.Background(), "session store circuit breaker state changed", map[string]interface{}{
+		"from": string(from),
+		"to":   string(to),
+	}, nil)
+}
@@ -0,0 +1,176 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// logEvent is an event received by fakeLogger
+type logEvent struct {
+	event string
+	data  map[string]interface{}
+	err   error
+}
+
+// fakeLogger records the events logged by the client
+type fakeLogger struct {
+	events []logEvent
+}
+
+func (f *fakeLogger) Event(ctx context.Context, event string, data map[string]interface{}, err error) {
+	f.events = append(f.events, logEvent{event: event, data: data, err: err})
+}
+
+func TestClient_RedactKey(t *testing.T) {
+	Convey("Given clients with different log redaction keys", t, func() {
+		client := &Client{logRedactionKey: []byte("secret")}
+		other := &Client{logRedactionKey: []byte("other secret")}
+
+		Convey("Then the redacted keys do not reveal the session ID or email", func() {
+			So(client.RedactKey("user@email.com"), ShouldHaveLength, redactedKeyLength)
+			So(client.RedactKey("user@email.com"), ShouldNotContainSubstring, "user")
+			So(client.RedactKey("1234"), ShouldNotContainSubstring, "1234")
+		})
+
+		Convey("Then the same key is always redacted the same way", func() {
+			So(client.RedactKey("1234"), ShouldEqual, client.RedactKey("1234"))
+			So(client.RedactKey("1234"), ShouldNotEqual, client.RedactKey("5678"))
+		})
+
+		Convey("Then a redacted email cannot be matched without the redaction key", func() {
+			So(other.RedactKey("user@email.com"), ShouldNotEqual, client.RedactKey("user@email.com"))
+		})
+	})
+
+	Convey("Given a client without a log redaction key", t, func() {
+		client := &Client{}
+
+		Convey("Then nothing is returned for a key", func() {
+			So(client.RedactKey("user@email.com"), ShouldBeEmpty)
+		})
+	})
+}
+
+func TestClient_Logging(t *testing.T) {
+	Convey("Given a client with a logger", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		logger := &fakeLogger{}
+		client.logger = logger
+		client.logRedactionKey = []byte("secret")
+
+		Convey("When an operation on a session fails", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				return redis.NewCmdResult(nil, errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+			}
+			_, err := client.GetByEmail("user@email.com")
+			So(err, ShouldNotBeNil)
+
+			Convey("Then the failure is logged with the error and the redacted email", func() {
+				So(logger.events, ShouldHaveLength, 1)
+				So(logger.events[0].event, ShouldEqual, "session operation failed")
+				So(logger.events[0].err, ShouldEqual, err)
+				So(logger.events[0].data["operation"], ShouldEqual, opGetByEmail)
+				So(logger.events[0].data["key"], ShouldEqual, client.RedactKey("user@email.com"))
+			})
+
+			Convey("And the email is not logged", func() {
+				So(fmt.Sprint(logger.events[0].data), ShouldNotContainSubstring, "user@email.com")
+			})
+		})
+
+		Convey("When an operation on a session fails without a log redaction key", func() {
+			client.logRedactionKey = nil
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				return redis.NewCmdResult(nil, errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+			}
+			_, err := client.GetByEmail("user@email.com")
+			So(err, ShouldNotBeNil)
+
+			Convey("Then the failure is logged without the email", func() {
+				So(logger.events, ShouldHaveLength, 1)
+				So(logger.events[0].data, ShouldNotContainKey, "key")
+				So(fmt.Sprint(logger.events[0].data), ShouldNotContainSubstring, "user@email.com")
+			})
+		})
+
+		Convey("When a session is not found", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				return redis.NewCmdResult(nil, redis.Nil)
+			}
+			_, err := client.GetByID("1234")
+			So(err, ShouldEqual, redis.Nil)
+
+			Convey("Then nothing is logged", func() {
+				So(logger.events, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When an operation is slow", func() {
+			mockRedisClient.PingFunc = func() *redis.StatusCmd {
+				time.Sleep(defaultSlowThreshold + 10*time.Millisecond)
+				return redis.NewStatusResult("PONG", nil)
+			}
+			So(client.Ping(), ShouldBeNil)
+
+			Convey("Then it is logged as slow", func() {
+				So(logger.events, ShouldHaveLength, 1)
+				So(logger.events[0].event, ShouldEqual, "slow session operation")
+				So(logger.events[0].data["operation"], ShouldEqual, opPing)
+				So(logger.events[0].err, ShouldBeNil)
+			})
+		})
+
+		Convey("When an operation is retried", func() {
+			client.retry = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+			mockRedisClient.PTTLFunc = func(key string) *redis.DurationCmd {
+				if len(mockRedisClient.PTTLCalls()) < 2 {
+					return redis.NewDurationResult(0, io.EOF)
+				}
+				return redis.NewDurationResult(time.Minute, nil)
+			}
+			_, err := client.TTL("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the retry is logged with the redacted session ID", func() {
+				So(logger.events, ShouldHaveLength, 1)
+				So(logger.events[0].event, ShouldEqual, "retrying session operation")
+				So(logger.events[0].err, ShouldEqual, io.EOF)
+				So(logger.events[0].data["operation"], ShouldEqual, opTTL)
+				So(logger.events[0].data["key"], ShouldEqual, client.RedactKey("1234"))
+				So(logger.events[0].data["attempt"], ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the circuit breaker opens", func() {
+			client.breaker = newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+			client.breaker.onTransition = client.logTransition
+			mockRedisClient.PingFunc = func() *redis.StatusCmd {
+				return redis.NewStatusResult("", errConnRefused)
+			}
+			So(client.Ping(), ShouldNotBeNil)
+
+			Convey("Then the transition is logged", func() {
+				var transitions []string
+				for _, e := range logger.events {
+					if strings.Contains(e.event, "circuit breaker") {
+						transitions = append(transitions, fmt.Sprintf("%v->%v", e.data["from"], e.data["to"]))
+					}
+				}
+				So(transitions, ShouldResemble, []string{"closed->open"})
+			})
+		})
+	})
+}
@@ -0,0 +1,174 @@
+package sessions
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ReadThroughCache - a LocalCache that GetByID reads sessions from before going to redis. Sessions read from redis are
+// only added if nothing has been removed from the cache since the read began, so that a session revoked while it was
+// being read is not cached after its revocation.
+type ReadThroughCache interface {
+	LocalCache
+	Get(id string) (*Session, bool)
+
+	// Generation returns a counter that changes every time a session is removed or the cache is purged
+	Generation() uint64
+
+	// AddIfGeneration adds the session if the generation is still gen, and reports whether it was added
+	AddIfGeneration(s *Session, gen uint64) bool
+}
+
+// CacheStats - hit and miss counts for an LRUCache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// LRUCache - a bounded, in-process, least recently used cache of sessions keyed by ID. Entries are evicted once they
+// are older than the max age, so the TTL of a session held in the cache is refreshed in redis at most once per max age.
+type LRUCache struct {
+	mu     sync.Mutex
+	size   int
+	maxAge time.Duration
+	ll     *list.List
+	items  map[string]*list.Element
+	hits   uint64
+	misses uint64
+	gen    uint64
+	now    func() time.Time
+}
+
+type lruEntry struct {
+	session *Session
+	added   time.Time
+}
+
+// NewLRUCache - returns a new LRUCache holding at most size sessions, each for no longer than maxAge
+func NewLRUCache(size int, maxAge time.Duration) *LRUCache {
+	return &LRUCache{
+		size:   size,
+		maxAge: maxAge,
+		ll:     list.New(),
+		items:  make(map[string]*list.Element),
+		now:    time.Now,
+	}
+}
+
+// Get - returns a copy of the cached session with the provided ID, if it is present and has not exceeded the max age
+func (l *LRUCache) Get(id string) (*Session, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[id]
+	if ok && l.now().Sub(el.Value.(*lruEntry).added) >= l.maxAge {
+		l.removeElement(el)
+		ok = false
+	}
+
+	if !ok {
+		l.misses++
+		return nil, false
+	}
+
+	l.hits++
+	l.ll.MoveToFront(el)
+	s := *el.Value.(*lruEntry).session
+	return &s, true
+}
+
+// Add - adds a copy of the session to the cache, evicting the least recently used session if the cache is full
+func (l *LRUCache) Add(s *Session) {
+	if s == nil || l.size <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.add(s)
+}
+
+// Generation - returns a counter that changes every time a session is removed or the cache is purged
+func (l *LRUCache) Generation() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.gen
+}
+
+// AddIfGeneration - adds a copy of the session to the cache as Add does, unless a session has been removed or the cache
+// purged since gen was returned by Generation. It reports whether the session was added.
+func (l *LRUCache) AddIfGeneration(s *Session, gen uint64) bool {
+	if s == nil || l.size <= 0 {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.gen != gen {
+		return false
+	}
+
+	l.add(s)
+	return true
+}
+
+// add adds a copy of the session to the cache, evicting the least recently used session if the cache is full. The
+// caller must hold the lock.
+func (l *LRUCache) add(s *Session) {
+	cp := *s
+	entry := &lruEntry{session: &cp, added: l.now()}
+
+	if el, ok := l.items[s.ID]; ok {
+		el.Value = entry
+		l.ll.MoveToFront(el)
+		return
+	}
+
+	l.items[s.ID] = l.ll.PushFront(entry)
+	if l.ll.Len() > l.size {
+		l.removeElement(l.ll.Back())
+	}
+}
+
+// Remove - removes the session with the provided ID from the cache
+func (l *LRUCache) Remove(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.gen++
+	if el, ok := l.items[id]; ok {
+		l.removeElement(el)
+	}
+}
+
+// Purge - removes every session from the cache
+func (l *LRUCache) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.gen++
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+}
+
+// Stats - returns the number of hits and misses since the cache was created, and the number of sessions it holds
+func (l *LRUCache) Stats() CacheStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return CacheStats{
+		Hits:   l.hits,
+		Misses: l.misses,
+		Size:   l.ll.Len(),
+	}
+}
+
+func (l *LRUCache) removeElement(el *list.Element) {
+	l.ll.Remove(el)
+	delete(l.items, el.Value.(*lruEntry).session.ID)
+}
@@ -0,0 +1,166 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLRUCache(t *testing.T) {
+	Convey("Given an LRU cache with room for two sessions", t, func() {
+		now := time.Now()
+		cache := NewLRUCache(2, time.Minute)
+		cache.now = func() time.Time { return now }
+
+		cache.Add(&Session{ID: "1"})
+		cache.Add(&Session{ID: "2"})
+
+		Convey("When a third session is added", func() {
+			cache.Get("1")
+			cache.Add(&Session{ID: "3"})
+
+			Convey("Then the least recently used session is evicted", func() {
+				_, ok := cache.Get("2")
+				So(ok, ShouldBeFalse)
+
+				_, ok = cache.Get("1")
+				So(ok, ShouldBeTrue)
+				_, ok = cache.Get("3")
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("When a session is older than the max age", func() {
+			now = now.Add(time.Minute)
+
+			Convey("Then it is not returned and is removed from the cache", func() {
+				_, ok := cache.Get("1")
+				So(ok, ShouldBeFalse)
+				So(cache.Stats().Size, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a returned session is modified", func() {
+			s, _ := cache.Get("1")
+			s.Email = "changed@email.com"
+
+			Convey("Then the cached session is unchanged", func() {
+				s, _ = cache.Get("1")
+				So(s.Email, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When sessions are removed and the cache purged", func() {
+			cache.Remove("1")
+			_, ok := cache.Get("1")
+			So(ok, ShouldBeFalse)
+
+			cache.Purge()
+
+			Convey("Then the cache is empty", func() {
+				So(cache.Stats().Size, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When a session is added at a generation that has since been invalidated", func() {
+			gen := cache.Generation()
+			cache.Remove("2")
+			removedAdded := cache.AddIfGeneration(&Session{ID: "3"}, gen)
+
+			gen = cache.Generation()
+			cache.Purge()
+			purgedAdded := cache.AddIfGeneration(&Session{ID: "4"}, gen)
+
+			Convey("Then it is not added", func() {
+				So(removedAdded, ShouldBeFalse)
+				So(purgedAdded, ShouldBeFalse)
+				So(cache.Stats().Size, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When a session is added at the current generation", func() {
+			added := cache.AddIfGeneration(&Session{ID: "3"}, cache.Generation())
+
+			Convey("Then it is added", func() {
+				So(added, ShouldBeTrue)
+				_, ok := cache.Get("3")
+				So(ok, ShouldBeTrue)
+			})
+		})
+
+		Convey("When sessions are read", func() {
+			cache.Get("1")
+			cache.Get("2")
+			cache.Get("3")
+
+			Convey("Then hits and misses are counted", func() {
+				So(cache.Stats(), ShouldResemble, CacheStats{Hits: 2, Misses: 1, Size: 2})
+			})
+		})
+	})
+}
+
+func TestClient_GetByIDReadThrough(t *testing.T) {
+	Convey("Given a client with an LRU cache", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+			return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+		}
+		cache := NewLRUCache(10, time.Minute)
+		client.localCache = cache
+
+		Convey("When the same session is read twice", func() {
+			first, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+			second, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then redis is only called, and the TTL refreshed, once", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 1)
+				So(cache.Stats(), ShouldResemble, CacheStats{Hits: 1, Misses: 1, Size: 1})
+			})
+
+			Convey("And the same session is returned each time", func() {
+				So(second.ID, ShouldEqual, first.ID)
+				So(second.ExpiresAt, ShouldEqual, first.ExpiresAt)
+			})
+		})
+
+		Convey("When the session is invalidated while it is being read from redis", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				// The revocation arrives after redis has returned the session but before it is cached
+				cache.Remove("1234")
+				return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+			}
+			_, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the session is not cached, so the next read goes to redis", func() {
+				So(cache.Stats().Size, ShouldEqual, 0)
+				_, err := client.GetByID("1234")
+				So(err, ShouldBeNil)
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When the session is set between reads", func() {
+			_, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+			err = client.SetSession(&Session{ID: "1234", Email: "user@email.com"})
+			So(err, ShouldBeNil)
+			_, err = client.GetByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then the session is read from redis again", func() {
+				So(mockRedisClient.EvalShaCalls(), ShouldHaveLength, 2)
+			})
+		})
+	})
+}
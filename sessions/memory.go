@@ -0,0 +1,231 @@
+package sessions
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	_ SessionStore = (*Client)(nil)
+	_ SessionStore = (*MemoryStore)(nil)
+)
+
+// MemoryStore - a SessionStore that holds sessions in memory, for local development without redis. As with the redis
+// client, sessions are stored under both their ID and email and expire once their TTL passes without being read.
+type MemoryStore struct {
+	mu               sync.Mutex
+	ttl              time.Duration
+	refreshThreshold time.Duration
+	csrf             bool
+	entries          map[string]memoryEntry
+	clock            func() time.Time
+}
+
+type memoryEntry struct {
+	session   Session
+	expiresAt time.Time
+}
+
+// NewMemoryStore - returns an empty memory store with the provided config options. Only TTL, RefreshThreshold, CSRF and
+// Clock are used.
+func NewMemoryStore(c Config) (*MemoryStore, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	return &MemoryStore{
+		ttl:              c.TTL,
+		refreshThreshold: c.RefreshThreshold,
+		csrf:             c.CSRF,
+		entries:          make(map[string]memoryEntry),
+		clock:            c.Clock,
+	}, nil
+}
+
+// SetSessionContext - stores the session under its ID and email, generating a CSRF secret for it if CSRF is enabled
+// and it has none
+func (m *MemoryStore) SetSessionContext(ctx context.Context, s *Session) (err error) {
+	if s == nil {
+		return ErrEmptySession
+	}
+
+	if m.csrf && s.CSRFSecret == "" {
+		if s.CSRFSecret, err = NewCSRFSecret(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := memoryEntry{session: *s, expiresAt: m.now().Add(m.ttl)}
+	e.session.ExpiresAt = time.Time{}
+	m.entries[s.ID] = e
+	m.entries[s.Email] = e
+
+	return nil
+}
+
+// GetByIDContext - gets a session by ID, extending its TTL
+func (m *MemoryStore) GetByIDContext(ctx context.Context, id string) (*Session, error) {
+	if id == "" {
+		return nil, ErrEmptySessionID
+	}
+
+	return m.getAndRefresh(id)
+}
+
+// GetByEmailContext - gets a session by email, extending its TTL
+func (m *MemoryStore) GetByEmailContext(ctx context.Context, email string) (*Session, error) {
+	if email == "" {
+		return nil, ErrEmptySessionEmail
+	}
+
+	return m.getAndRefresh(email)
+}
+
+// PeekByIDContext - gets a session by ID and its remaining TTL without extending it
+func (m *MemoryStore) PeekByIDContext(ctx context.Context, id string) (*Session, time.Duration, error) {
+	if id == "" {
+		return nil, 0, ErrEmptySessionID
+	}
+
+	return m.peek(id)
+}
+
+// PeekByEmailContext - gets a session by email and its remaining TTL without extending it
+func (m *MemoryStore) PeekByEmailContext(ctx context.Context, email string) (*Session, time.Duration, error) {
+	if email == "" {
+		return nil, 0, ErrEmptySessionEmail
+	}
+
+	return m.peek(email)
+}
+
+// TTLContext - returns the remaining TTL of the session with the provided ID
+func (m *MemoryStore) TTLContext(ctx context.Context, id string) (time.Duration, error) {
+	_, ttl, err := m.PeekByIDContext(ctx, id)
+	return ttl, err
+}
+
+// SessionTTL - returns the TTL that sessions are stored with, and extended to when they are read
+func (m *MemoryStore) SessionTTL() time.Duration {
+	return m.ttl
+}
+
+// DeleteByIDContext - removes the session with the provided ID, and its email key if it still belongs to the session
+func (m *MemoryStore) DeleteByIDContext(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrEmptySessionID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	delete(m.entries, id)
+	if m.holds(e.session.Email, id) {
+		delete(m.entries, e.session.Email)
+	}
+
+	return nil
+}
+
+// DeleteAllContext - removes every session
+func (m *MemoryStore) DeleteAllContext(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]memoryEntry)
+
+	return nil
+}
+
+// PingContext - always succeeds, as there is no backend to reach
+func (m *MemoryStore) PingContext(ctx context.Context) error {
+	return nil
+}
+
+// Close - does nothing, as there is no connection to close
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// getAndRefresh returns the session stored under key, extending its TTL and recording when it was accessed once
+// RefreshThreshold has passed since the TTL was last extended. Only keys still holding the session are refreshed.
+func (m *MemoryStore) getAndRefresh(key string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	now := m.now()
+	if e.expiresAt.Sub(now) <= m.ttl-m.refreshThreshold {
+		e.session.LastAccessed = now
+		e.expiresAt = now.Add(m.ttl)
+		for _, k := range []string{e.session.ID, e.session.Email} {
+			if m.holds(k, e.session.ID) {
+				m.entries[k] = e
+			}
+		}
+	}
+
+	s := e.session
+	s.LastAccessed = now
+	s.ExpiresAt = e.expiresAt
+
+	return &s, nil
+}
+
+// peek returns the session stored under key and its remaining TTL
+func (m *MemoryStore) peek(key string) (*Session, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return nil, 0, ErrSessionNotFound
+	}
+
+	s := e.session
+	s.ExpiresAt = e.expiresAt
+
+	return &s, e.expiresAt.Sub(m.now()), nil
+}
+
+// get returns the unexpired entry stored under key, removing it if it has expired. m.mu must be held.
+func (m *MemoryStore) get(key string) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+
+	if !m.now().Before(e.expiresAt) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+
+	return e, true
+}
+
+// holds reports whether key holds an unexpired copy of the session with the provided ID. m.mu must be held.
+func (m *MemoryStore) holds(key, id string) bool {
+	e, ok := m.get(key)
+	return ok && e.session.ID == id
+}
+
+// now returns the current time from the clock, if one is configured
+func (m *MemoryStore) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+	return m.clock()
+}
@@ -0,0 +1,150 @@
+package sessions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewMemoryStore(t *testing.T) {
+	Convey("Given an invalid refresh threshold", t, func() {
+
+		Convey("When the memory store is created", func() {
+			m, err := NewMemoryStore(Config{TTL: time.Minute, RefreshThreshold: time.Minute})
+
+			Convey("Then the store will not be created and the invalid threshold error is returned", func() {
+				So(m, ShouldBeNil)
+				So(err, ShouldEqual, ErrInvalidThreshold)
+			})
+		})
+	})
+}
+
+func TestSessionStore(t *testing.T) {
+	stores := map[string]func(fake *FakeRedis) SessionStore{
+		"redis client": func(fake *FakeRedis) SessionStore {
+			return newFakeClient(fake, Config{})
+		},
+		"memory store": func(fake *FakeRedis) SessionStore {
+			m, err := NewMemoryStore(Config{TTL: testTTL, Clock: fake.Now})
+			So(err, ShouldBeNil)
+			return m
+		},
+	}
+
+	for name, newStore := range stores {
+		Convey("Given a session in the "+name, t, func() {
+			ctx := context.Background()
+			fake := newFakeRedis()
+			store := newStore(fake)
+
+			start := fake.Now().Add(-time.Hour)
+			So(store.SetSessionContext(ctx, &Session{ID: "1234", Email: "user@email.com", Start: start, LastAccessed: start}), ShouldBeNil)
+
+			Convey("When it is read by ID and email", func() {
+				byID, err := store.GetByIDContext(ctx, "1234")
+				So(err, ShouldBeNil)
+				byEmail, err := store.GetByEmailContext(ctx, "user@email.com")
+				So(err, ShouldBeNil)
+
+				Convey("Then the same session is returned", func() {
+					So(byID.Email, ShouldEqual, "user@email.com")
+					So(byEmail.ID, ShouldEqual, "1234")
+					So(byID.Start, ShouldHappenWithin, time.Millisecond, start)
+					So(byID.LastAccessed, ShouldEqual, fake.Now())
+					So(byID.ExpiresAt, ShouldEqual, fake.Now().Add(testTTL))
+				})
+			})
+
+			Convey("Then the store reports the TTL sessions are stored with", func() {
+				So(store.SessionTTL(), ShouldEqual, testTTL)
+			})
+
+			Convey("When it is read shortly before it would expire", func() {
+				fake.Advance(testTTL - time.Minute)
+				_, err := store.GetByEmailContext(ctx, "user@email.com")
+				So(err, ShouldBeNil)
+				fake.Advance(2 * time.Minute)
+
+				Convey("Then its TTL was extended under both keys", func() {
+					ttl, err := store.TTLContext(ctx, "1234")
+					So(err, ShouldBeNil)
+					So(ttl, ShouldEqual, testTTL-2*time.Minute)
+
+					s, _, err := store.PeekByEmailContext(ctx, "user@email.com")
+					So(err, ShouldBeNil)
+					So(s.LastAccessed, ShouldHappenWithin, time.Millisecond, fake.Now().Add(-2*time.Minute))
+				})
+			})
+
+			Convey("When its TTL passes", func() {
+				fake.Advance(testTTL)
+
+				Convey("Then it is not found", func() {
+					_, err := store.GetByIDContext(ctx, "1234")
+					So(err, ShouldEqual, ErrSessionNotFound)
+					_, _, err = store.PeekByEmailContext(ctx, "user@email.com")
+					So(err, ShouldEqual, ErrSessionNotFound)
+					_, err = store.TTLContext(ctx, "1234")
+					So(err, ShouldEqual, ErrSessionNotFound)
+				})
+			})
+
+			Convey("When a newer session is stored for the same email and the older one is read", func() {
+				fake.Advance(time.Minute)
+				So(store.SetSessionContext(ctx, &Session{ID: "5678", Email: "user@email.com"}), ShouldBeNil)
+				_, err := store.GetByIDContext(ctx, "1234")
+				So(err, ShouldBeNil)
+
+				Convey("Then the email still finds the newer session", func() {
+					s, err := store.GetByEmailContext(ctx, "user@email.com")
+					So(err, ShouldBeNil)
+					So(s.ID, ShouldEqual, "5678")
+				})
+			})
+
+			Convey("When it is deleted", func() {
+				So(store.DeleteByIDContext(ctx, "1234"), ShouldBeNil)
+
+				Convey("Then it is not found by ID or email", func() {
+					_, _, err := store.PeekByIDContext(ctx, "1234")
+					So(err, ShouldEqual, ErrSessionNotFound)
+					_, _, err = store.PeekByEmailContext(ctx, "user@email.com")
+					So(err, ShouldEqual, ErrSessionNotFound)
+				})
+
+				Convey("And deleting it again returns ErrSessionNotFound", func() {
+					So(store.DeleteByIDContext(ctx, "1234"), ShouldEqual, ErrSessionNotFound)
+				})
+			})
+
+			Convey("When every session is deleted", func() {
+				So(store.DeleteAllContext(ctx), ShouldBeNil)
+
+				Convey("Then it is not found", func() {
+					_, err := store.GetByIDContext(ctx, "1234")
+					So(err, ShouldEqual, ErrSessionNotFound)
+				})
+			})
+
+			Convey("When it is looked up without an ID or email", func() {
+				_, idErr := store.GetByIDContext(ctx, "")
+				_, _, emailErr := store.PeekByEmailContext(ctx, "")
+
+				Convey("Then the empty value errors are returned", func() {
+					So(idErr, ShouldEqual, ErrEmptySessionID)
+					So(emailErr, ShouldEqual, ErrEmptySessionEmail)
+				})
+			})
+
+			Convey("When the store is pinged", func() {
+
+				Convey("Then it is reachable", func() {
+					So(store.PingContext(ctx), ShouldBeNil)
+				})
+			})
+		})
+	}
+}
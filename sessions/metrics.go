@@ -0,0 +1,196 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsNamespace prefixes the names of every metric collected from the client
+const metricsNamespace = "sessions_client"
+
+// activeSessionsTimeout is how long a collection waits for the active sessions to be counted before leaving them out
+const activeSessionsTimeout = 250 * time.Millisecond
+
+var (
+	poolHitsDesc = prometheus.NewDesc(metricsNamespace+"_pool_hits_total",
+		"Number of times a free connection was found in the redis connection pool", nil, nil)
+	poolMissesDesc = prometheus.NewDesc(metricsNamespace+"_pool_misses_total",
+		"Number of times a free connection was not found in the redis connection pool", nil, nil)
+	poolTimeoutsDesc = prometheus.NewDesc(metricsNamespace+"_pool_timeouts_total",
+		"Number of times waiting for a connection from the redis connection pool timed out", nil, nil)
+	poolTotalConnsDesc = prometheus.NewDesc(metricsNamespace+"_pool_connections",
+		"Number of connections in the redis connection pool", nil, nil)
+	poolIdleConnsDesc = prometheus.NewDesc(metricsNamespace+"_pool_idle_connections",
+		"Number of idle connections in the redis connection pool", nil, nil)
+	poolStaleConnsDesc = prometheus.NewDesc(metricsNamespace+"_pool_stale_connections_total",
+		"Number of stale connections removed from the redis connection pool", nil, nil)
+	activeSessionsDesc = prometheus.NewDesc(metricsNamespace+"_active_sessions",
+		"Number of sessions in redis that have not expired, from the expiry index", nil, nil)
+)
+
+// metrics holds the prometheus collectors updated by the client as operations complete
+type metrics struct {
+	duration *prometheus.HistogramVec
+	hits     *prometheus.CounterVec
+	misses   *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	sessions *prometheus.CounterVec
+	slowOps  *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Time taken by session operations, including retries",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"method"}),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "hits_total",
+			Help:      "Number of session lookups that found a session",
+		}, []string{"method"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "misses_total",
+			Help:      "Number of session lookups that did not find a session",
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "errors_total",
+			Help:      "Number of session operations that returned an error",
+		}, []string{"method"}),
+		sessions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "sessions_total",
+			Help:      "Number of sessions created, refreshed, revoked and expired by the client",
+		}, []string{"event"}),
+		slowOps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "slow_operations_total",
+			Help:      "Number of session operations that took longer than the slow threshold",
+		}, []string{"method", "namespace"}),
+	}
+}
+
+// observe records the duration and outcome of an operation. ErrSessionNotFound is a miss rather than an error.
+func (m *metrics) observe(op string, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.duration.WithLabelValues(op).Observe(elapsed.Seconds())
+
+	if err != nil && err != ErrSessionNotFound {
+		m.errors.WithLabelValues(op).Inc()
+	}
+}
+
+// lookup records the number of sessions an operation found and did not find
+func (m *metrics) lookup(op string, hits, misses int) {
+	if m == nil {
+		return
+	}
+
+	m.hits.WithLabelValues(op).Add(float64(hits))
+	m.misses.WithLabelValues(op).Add(float64(misses))
+}
+
+// session records a change in the lifecycle of a session
+func (m *metrics) session(t EventType, n int) {
+	if m == nil {
+		return
+	}
+
+	m.sessions.WithLabelValues(string(t)).Add(float64(n))
+}
+
+// slow records an operation that took longer than the slow threshold
+func (m *metrics) slow(op SlowOperation) {
+	if m == nil {
+		return
+	}
+
+	m.slowOps.WithLabelValues(op.Command, string(op.KeyNamespace)).Inc()
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.duration, m.hits, m.misses, m.errors, m.sessions, m.slowOps}
+}
+
+// Describe - implements prometheus.Collector so the client can be registered with a prometheus registry
+func (c *Client) Describe(ch chan<- *prometheus.Desc) {
+	if c.metrics != nil {
+		for _, collector := range c.metrics.collectors() {
+			collector.Describe(ch)
+		}
+	}
+
+	ch <- poolHitsDesc
+	ch <- poolMissesDesc
+	ch <- poolTimeoutsDesc
+	ch <- poolTotalConnsDesc
+	ch <- poolIdleConnsDesc
+	ch <- poolStaleConnsDesc
+	ch <- activeSessionsDesc
+}
+
+// Collect - implements prometheus.Collector, reporting operation metrics along with the current redis connection
+// pool statistics and number of active sessions. The active sessions are counted on each collection with a single
+// attempt that is not recorded as an operation, and the gauge is left out if that fails or takes longer than
+// activeSessionsTimeout.
+func (c *Client) Collect(ch chan<- prometheus.Metric) {
+	if c.metrics != nil {
+		for _, collector := range c.metrics.collectors() {
+			collector.Collect(ch)
+		}
+	}
+
+	if active, err := c.collectActive(activeSessionsTimeout); err == nil {
+		ch <- prometheus.MustNewConstMetric(activeSessionsDesc, prometheus.GaugeValue, float64(active))
+	}
+
+	stats := c.client.PoolStats()
+	if stats == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(poolHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(poolMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(poolTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(poolTotalConnsDesc, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(poolIdleConnsDesc, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(poolStaleConnsDesc, prometheus.CounterValue, float64(stats.StaleConns))
+}
+
+// collectActive counts the active sessions for a collection. It makes one attempt through the circuit breaker, without
+// retries, and gives up waiting after timeout so that a slow redis does not hold up the scrape.
+func (c *Client) collectActive(timeout time.Duration) (int64, error) {
+	type result struct {
+		active int64
+		err    error
+	}
+
+	res := make(chan result, 1)
+	go func() {
+		var active int64
+		err := c.attempt(func() (err error) {
+			active, err = c.countActive()
+			return err
+		})
+		res <- result{active, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-res:
+		return r.active, r.err
+	case <-timer.C:
+		return 0, context.DeadlineExceeded
+	}
+}
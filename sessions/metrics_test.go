@@ -0,0 +1,159 @@
+package sessions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Metrics(t *testing.T) {
+	Convey("Given a client with metrics", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.PoolStatsFunc = func() *redis.PoolStats {
+			return &redis.PoolStats{Hits: 10, Misses: 2, Timeouts: 1, TotalConns: 5, IdleConns: 3}
+		}
+		mockRedisClient.ZCountFunc = func(key, min, max string) *redis.IntCmd {
+			return redis.NewIntResult(42, nil)
+		}
+		client.metrics = newMetrics()
+
+		Convey("When sessions are found and not found", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				if keys[0] == "1234" {
+					return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+				}
+				return redis.NewCmdResult(nil, redis.Nil)
+			}
+			_, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+			_, err = client.GetByID("5678")
+			So(err, ShouldEqual, redis.Nil)
+
+			Convey("Then hits and misses are counted by method without counting an error", func() {
+				So(testutil.ToFloat64(client.metrics.hits.WithLabelValues(opGetByID)), ShouldEqual, 1)
+				So(testutil.ToFloat64(client.metrics.misses.WithLabelValues(opGetByID)), ShouldEqual, 1)
+				So(testutil.ToFloat64(client.metrics.errors.WithLabelValues(opGetByID)), ShouldEqual, 0)
+			})
+
+			Convey("And the latency of each call is observed", func() {
+				So(testutil.CollectAndCount(client.metrics.duration, metricsNamespace+"_operation_duration_seconds"), ShouldEqual, 1)
+			})
+
+			Convey("And the refreshed session is counted", func() {
+				So(testutil.ToFloat64(client.metrics.sessions.WithLabelValues(string(SessionRefreshed))), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When a session is set and deleted", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+			}
+			So(client.SetSession(&Session{ID: "1234", Email: "user@email.com"}), ShouldBeNil)
+			So(client.DeleteByID("1234"), ShouldBeNil)
+
+			Convey("Then the created and revoked sessions are counted", func() {
+				So(testutil.ToFloat64(client.metrics.sessions.WithLabelValues(string(SessionCreated))), ShouldEqual, 1)
+				So(testutil.ToFloat64(client.metrics.sessions.WithLabelValues(string(SessionRevoked))), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When an operation returns an error", func() {
+			mockRedisClient.PingFunc = func() *redis.StatusCmd {
+				return redis.NewStatusResult("", errors.New("NOAUTH Authentication required."))
+			}
+			So(client.Ping(), ShouldNotBeNil)
+
+			Convey("Then the error is counted for the method", func() {
+				So(testutil.ToFloat64(client.metrics.errors.WithLabelValues(opPing)), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the client is registered and gathered", func() {
+			registry := prometheus.NewPedanticRegistry()
+			So(registry.Register(client), ShouldBeNil)
+
+			err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP sessions_client_pool_connections Number of connections in the redis connection pool
+# TYPE sessions_client_pool_connections gauge
+sessions_client_pool_connections 5
+# HELP sessions_client_pool_hits_total Number of times a free connection was found in the redis connection pool
+# TYPE sessions_client_pool_hits_total counter
+sessions_client_pool_hits_total 10
+# HELP sessions_client_pool_idle_connections Number of idle connections in the redis connection pool
+# TYPE sessions_client_pool_idle_connections gauge
+sessions_client_pool_idle_connections 3
+# HELP sessions_client_pool_timeouts_total Number of times waiting for a connection from the redis connection pool timed out
+# TYPE sessions_client_pool_timeouts_total counter
+sessions_client_pool_timeouts_total 1
+`), "sessions_client_pool_connections", "sessions_client_pool_hits_total", "sessions_client_pool_idle_connections",
+				"sessions_client_pool_timeouts_total")
+
+			Convey("Then the redis connection pool statistics are reported", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("And the active sessions are counted from the expiry index", func() {
+				err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP sessions_client_active_sessions Number of sessions in redis that have not expired, from the expiry index
+# TYPE sessions_client_active_sessions gauge
+sessions_client_active_sessions 42
+`), "sessions_client_active_sessions")
+				So(err, ShouldBeNil)
+				So(mockRedisClient.ZCountCalls()[0].Key, ShouldEqual, expiryIndexKey)
+			})
+
+			Convey("And counting them is not recorded as an operation", func() {
+				So(testutil.CollectAndCount(client.metrics.duration), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the active sessions cannot be counted", func() {
+			mockRedisClient.ZCountFunc = func(key, min, max string) *redis.IntCmd {
+				return redis.NewIntResult(0, errors.New("connection refused"))
+			}
+			client.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+			registry := prometheus.NewPedanticRegistry()
+			So(registry.Register(client), ShouldBeNil)
+
+			count, err := testutil.GatherAndCount(registry, "sessions_client_active_sessions")
+
+			Convey("Then the gauge is left out rather than failing the collection", func() {
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+			})
+
+			Convey("And the count is not retried", func() {
+				So(mockRedisClient.ZCountCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When counting the active sessions takes longer than the timeout", func() {
+			mockRedisClient.ZCountFunc = func(key, min, max string) *redis.IntCmd {
+				time.Sleep(2 * activeSessionsTimeout)
+				return redis.NewIntResult(42, nil)
+			}
+			registry := prometheus.NewPedanticRegistry()
+			So(registry.Register(client), ShouldBeNil)
+
+			start := time.Now()
+			count, err := testutil.GatherAndCount(registry, "sessions_client_active_sessions")
+
+			Convey("Then the gauge is left out without waiting for redis", func() {
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+				So(time.Since(start), ShouldBeLessThan, 2*activeSessionsTimeout)
+			})
+		})
+	})
+}
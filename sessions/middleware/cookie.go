@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+)
+
+var (
+	ErrNilSessionStore       = errors.New("session store required but was nil")
+	ErrInvalidMaxLifetime    = errors.New("cookie max lifetime should not be negative")
+	ErrInvalidCookieSameSite = errors.New("cookie same site mode none requires a secure cookie")
+	ErrNoSessionCookie       = errors.New("request has no session cookie")
+	ErrInvalidSignature      = errors.New("session cookie signature is invalid")
+)
+
+// CookieConfig - config options for session cookies
+type CookieConfig struct {
+	// Name is the name of the cookie. It defaults to DefaultCookieName.
+	Name string
+
+	Domain string
+	Path   string
+
+	// MaxLifetime optionally limits how long after it started a session is accepted, however often it is used. Its
+	// cookie expires at the end of the lifetime, and the middleware rejects the session once it has passed.
+	MaxLifetime time.Duration
+
+	// Insecure allows the cookie to be sent over plain HTTP, for local development. Cookies are Secure by default.
+	Insecure bool
+
+	// SameSite defaults to http.SameSiteLaxMode
+	SameSite http.SameSite
+
+	// SigningKey optionally signs cookie values with HMAC-SHA256, so that cookies holding session IDs that were not
+	// issued by the service are rejected without being looked up
+	SigningKey []byte
+}
+
+// Cookies - writes, clears and reads session cookies. Cookies are always HttpOnly.
+type Cookies struct {
+	cfg CookieConfig
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewCookies - returns session cookie helpers with the provided config options for sessions held in store. Cookies
+// expire after the TTL of the store, so that they expire with the session if it is not used.
+func NewCookies(store sessions.SessionStore, cfg CookieConfig) (*Cookies, error) {
+	if store == nil {
+		return nil, ErrNilSessionStore
+	}
+
+	if cfg.MaxLifetime < 0 {
+		return nil, ErrInvalidMaxLifetime
+	}
+
+	if cfg.SameSite == http.SameSiteNoneMode && cfg.Insecure {
+		return nil, ErrInvalidCookieSameSite
+	}
+
+	if cfg.Name == "" {
+		cfg.Name = DefaultCookieName
+	}
+
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+
+	return &Cookies{cfg: cfg, ttl: store.SessionTTL(), now: time.Now}, nil
+}
+
+// Name - returns the name of the session cookie
+func (c *Cookies) Name() string {
+	return c.cfg.Name
+}
+
+// Write - sets the session cookie for s. It expires after the TTL of the store, or at the end of the session's max
+// lifetime if that is sooner. If the session has less than a second of its lifetime left the cookie is cleared.
+func (c *Cookies) Write(w http.ResponseWriter, s *sessions.Session) error {
+	if s == nil {
+		return sessions.ErrEmptySession
+	}
+
+	if s.ID == "" {
+		return sessions.ErrEmptySessionID
+	}
+
+	maxAge := c.ttl
+	if remaining, ok := c.remainingLifetime(s); ok && remaining < maxAge {
+		maxAge = remaining
+	}
+
+	// Max-Age is in whole seconds and zero would omit it, so a session with under a second left is cleared
+	if maxAge < time.Second {
+		c.Clear(w)
+		return nil
+	}
+
+	cookie := c.cookie(c.sign(s.ID))
+	cookie.MaxAge = int(maxAge / time.Second)
+	http.SetCookie(w, cookie)
+
+	return nil
+}
+
+// Value - returns the value that presents the session ID in the cookie, or in the header read by the middleware, which
+// is signed if a signing key is configured
+func (c *Cookies) Value(id string) string {
+	return c.sign(id)
+}
+
+// Clear - removes the session cookie from the client
+func (c *Cookies) Clear(w http.ResponseWriter) {
+	cookie := c.cookie("")
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+}
+
+// Read - returns the session ID from the session cookie of the request, verifying its signature if a signing key is
+// configured
+func (c *Cookies) Read(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(c.cfg.Name)
+	if err != nil || cookie.Value == "" {
+		return "", ErrNoSessionCookie
+	}
+
+	return c.verify(cookie.Value)
+}
+
+// remainingLifetime returns how long is left of the session's max lifetime, which is negative once it has passed, and
+// whether a max lifetime is configured
+func (c *Cookies) remainingLifetime(s *sessions.Session) (time.Duration, bool) {
+	if c.cfg.MaxLifetime <= 0 {
+		return 0, false
+	}
+
+	return s.Start.Add(c.cfg.MaxLifetime).Sub(c.now()), true
+}
+
+// pastMaxLifetime reports whether the session's max lifetime has passed
+func (c *Cookies) pastMaxLifetime(s *sessions.Session) bool {
+	remaining, ok := c.remainingLifetime(s)
+	return ok && remaining <= 0
+}
+
+// cookie returns the session cookie with the configured attributes and the provided value
+func (c *Cookies) cookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     c.cfg.Name,
+		Value:    value,
+		Domain:   c.cfg.Domain,
+		Path:     c.cfg.Path,
+		Secure:   !c.cfg.Insecure,
+		HttpOnly: true,
+		SameSite: c.cfg.SameSite,
+	}
+}
+
+// sign returns the cookie value for id, which is followed by its signature if a signing key is configured
+func (c *Cookies) sign(id string) string {
+	if len(c.cfg.SigningKey) == 0 {
+		return id
+	}
+
+	return id + "." + base64.RawURLEncoding.EncodeToString(c.mac(id))
+}
+
+// verify returns the session ID from a cookie value, checking its signature if a signing key is configured
+func (c *Cookies) verify(value string) (string, error) {
+	if len(c.cfg.SigningKey) == 0 {
+		return value, nil
+	}
+
+	i := strings.LastIndexByte(value, '.')
+	if i <= 0 {
+		return "", ErrInvalidSignature
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(value[i+1:])
+	if err != nil || !hmac.Equal(sig, c.mac(value[:i])) {
+		return "", ErrInvalidSignature
+	}
+
+	return value[:i], nil
+}
+
+// mac returns the HMAC-SHA256 of id with the signing key
+func (c *Cookies) mac(id string) []byte {
+	h := hmac.New(sha256.New, c.cfg.SigningKey)
+	h.Write([]byte(id))
+	return h.Sum(nil)
+}
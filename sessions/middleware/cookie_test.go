@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newStore returns an empty memory store holding sessions for ttl
+func newStore(ttl time.Duration) *sessions.MemoryStore {
+	store, err := sessions.NewMemoryStore(sessions.Config{TTL: ttl})
+	So(err, ShouldBeNil)
+	return store
+}
+
+// written returns the cookie set on rec
+func written(rec *httptest.ResponseRecorder) *http.Cookie {
+	cookies := rec.Result().Cookies()
+	So(cookies, ShouldHaveLength, 1)
+	return cookies[0]
+}
+
+func TestNewCookies(t *testing.T) {
+	Convey("Given no session store", t, func() {
+
+		Convey("When the cookies are created", func() {
+			c, err := NewCookies(nil, CookieConfig{})
+
+			Convey("Then the nil session store error is returned", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldEqual, ErrNilSessionStore)
+			})
+		})
+	})
+
+	Convey("Given cookie config for insecure cookies with same site mode none", t, func() {
+
+		Convey("When the cookies are created", func() {
+			c, err := NewCookies(newStore(time.Minute), CookieConfig{Insecure: true, SameSite: http.SameSiteNoneMode})
+
+			Convey("Then the invalid same site error is returned, as browsers reject such cookies", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldEqual, ErrInvalidCookieSameSite)
+			})
+		})
+	})
+}
+
+func TestCookies_Write(t *testing.T) {
+	Convey("Given cookies for a store with a TTL, with a max lifetime", t, func() {
+		c, err := NewCookies(newStore(30*time.Minute), CookieConfig{MaxLifetime: 8 * time.Hour, Domain: "ons.gov.uk"})
+		So(err, ShouldBeNil)
+		now := time.Now()
+		c.now = func() time.Time { return now }
+
+		rec := httptest.NewRecorder()
+
+		Convey("When the cookie is written for a new session", func() {
+			So(c.Write(rec, &sessions.Session{ID: "1234", Start: now}), ShouldBeNil)
+			cookie := written(rec)
+
+			Convey("Then it holds the session ID with secure attributes and expires with the TTL of the store", func() {
+				So(cookie.Name, ShouldEqual, DefaultCookieName)
+				So(cookie.Value, ShouldEqual, "1234")
+				So(cookie.Domain, ShouldEqual, "ons.gov.uk")
+				So(cookie.Path, ShouldEqual, "/")
+				So(cookie.Secure, ShouldBeTrue)
+				So(cookie.HttpOnly, ShouldBeTrue)
+				So(cookie.SameSite, ShouldEqual, http.SameSiteLaxMode)
+				So(cookie.MaxAge, ShouldEqual, 1800)
+			})
+		})
+
+		Convey("When the cookie is written for a session near the end of its max lifetime", func() {
+			So(c.Write(rec, &sessions.Session{ID: "1234", Start: now.Add(-8*time.Hour + 10*time.Minute)}), ShouldBeNil)
+
+			Convey("Then it expires at the end of the lifetime", func() {
+				So(written(rec).MaxAge, ShouldEqual, 600)
+			})
+		})
+
+		Convey("When the cookie is written for a session past its max lifetime", func() {
+			So(c.Write(rec, &sessions.Session{ID: "1234", Start: now.Add(-9 * time.Hour)}), ShouldBeNil)
+
+			Convey("Then the cookie is cleared", func() {
+				cookie := written(rec)
+				So(cookie.Value, ShouldBeEmpty)
+				So(cookie.MaxAge, ShouldEqual, -1)
+			})
+		})
+
+		Convey("When the cookie is written without a session", func() {
+			err := c.Write(rec, nil)
+
+			Convey("Then the empty session error is returned", func() {
+				So(err, ShouldEqual, sessions.ErrEmptySession)
+			})
+		})
+
+		Convey("When the cookie is cleared", func() {
+			c.Clear(rec)
+			cookie := written(rec)
+
+			Convey("Then it is expired with the same attributes", func() {
+				So(cookie.Name, ShouldEqual, DefaultCookieName)
+				So(cookie.Value, ShouldBeEmpty)
+				So(cookie.MaxAge, ShouldEqual, -1)
+				So(cookie.Secure, ShouldBeTrue)
+				So(cookie.HttpOnly, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestCookies_Read(t *testing.T) {
+	Convey("Given cookies with a signing key", t, func() {
+		c, err := NewCookies(newStore(time.Minute), CookieConfig{SigningKey: []byte("secret")})
+		So(err, ShouldBeNil)
+
+		rec := httptest.NewRecorder()
+		So(c.Write(rec, &sessions.Session{ID: "1234", Start: time.Now()}), ShouldBeNil)
+		signed := written(rec)
+
+		Convey("When a cookie it wrote is read", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(signed)
+			id, err := c.Read(req)
+
+			Convey("Then the session ID is returned", func() {
+				So(signed.Value, ShouldStartWith, "1234.")
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, "1234")
+			})
+		})
+
+		Convey("When a cookie with a forged ID is read", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "5678" + signed.Value[4:]})
+			_, err := c.Read(req)
+
+			Convey("Then the invalid signature error is returned", func() {
+				So(err, ShouldEqual, ErrInvalidSignature)
+			})
+		})
+
+		Convey("When an unsigned cookie is read", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "1234"})
+			_, err := c.Read(req)
+
+			Convey("Then the invalid signature error is returned", func() {
+				So(err, ShouldEqual, ErrInvalidSignature)
+			})
+		})
+
+		Convey("When there is no cookie", func() {
+			_, err := c.Read(httptest.NewRequest(http.MethodGet, "/", nil))
+
+			Convey("Then the no cookie error is returned", func() {
+				So(err, ShouldEqual, ErrNoSessionCookie)
+			})
+		})
+
+		Convey("When the middleware reads a forged cookie", func() {
+			store := failingStore{err: sessions.ErrSessionNotFound}
+			looked := false
+			lookup := lookupCounter{SessionStore: store, looked: &looked}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "5678" + signed.Value[4:]})
+			_, s, called := serve(lookup, Config{Cookies: c}, req)
+
+			Convey("Then the request is unauthenticated without the store being called", func() {
+				So(called, ShouldBeTrue)
+				So(s, ShouldBeNil)
+				So(looked, ShouldBeFalse)
+			})
+		})
+
+		Convey("When the middleware reads an unsigned session ID from the header", func() {
+			store := failingStore{err: sessions.ErrSessionNotFound}
+			looked := false
+			lookup := lookupCounter{SessionStore: store, looked: &looked}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer 1234")
+			_, s, called := serve(lookup, Config{Cookies: c, HeaderName: "Authorization"}, req)
+
+			Convey("Then the request is unauthenticated without the store being called", func() {
+				So(called, ShouldBeTrue)
+				So(s, ShouldBeNil)
+				So(looked, ShouldBeFalse)
+			})
+		})
+
+		Convey("When the middleware reads a signed session ID from the header", func() {
+			store := newStore(time.Minute)
+			So(store.SetSessionContext(context.Background(), &sessions.Session{ID: "1234", Email: "user@email.com"}), ShouldBeNil)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+c.Value("1234"))
+			_, s, _ := serve(store, Config{Cookies: c, HeaderName: "Authorization"}, req)
+
+			Convey("Then the session is loaded", func() {
+				So(s, ShouldNotBeNil)
+				So(s.ID, ShouldEqual, "1234")
+			})
+		})
+	})
+}
+
+// lookupCounter records whether the session store was asked for a session
+type lookupCounter struct {
+	sessions.SessionStore
+	looked *bool
+}
+
+func (l lookupCounter) GetByIDContext(ctx context.Context, id string) (*sessions.Session, error) {
+	*l.looked = true
+	return l.SessionStore.GetByIDContext(ctx, id)
+}
@@ -0,0 +1,151 @@
+// Package middleware provides HTTP middleware that loads the session for each request from a sessions.SessionStore,
+// such as a sessions.Client, and makes it available from the request context.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+)
+
+// DefaultCookieName is the cookie the session ID is read from if Config does not name one
+const DefaultCookieName = "session_id"
+
+// contextKey is the key of the session in the request context
+type contextKey struct{}
+
+// Config - config options for the session middleware
+type Config struct {
+	// CookieName is the cookie holding the session ID. It defaults to DefaultCookieName.
+	CookieName string
+
+	// Cookies optionally reads the session cookie instead of CookieName, so that cookies with an invalid signature are
+	// treated as unauthenticated without being looked up in the store. Sessions past the max lifetime of the cookies
+	// are also treated as unauthenticated, and their cookie is cleared. The cookie of a session loaded from it is
+	// written again, so that it expires with the session's extended TTL.
+	Cookies *Cookies
+
+	// HeaderName optionally names a header holding the session ID, which is read if the request has no session
+	// cookie. A "Bearer " prefix on its value is ignored. If Cookies has a signing key the value must be signed like a
+	// cookie value, and values with an invalid signature are treated as unauthenticated.
+	HeaderName string
+
+	// OnUnauthenticated is called instead of the next handler when the request has no session ID, or its session
+	// does not exist or is past its max lifetime. If it is nil the next handler is called without a session in the context.
+	OnUnauthenticated func(w http.ResponseWriter, r *http.Request)
+
+	// OnError is called instead of the next handler when the session could not be loaded. If it is nil a 500
+	// Internal Server Error is returned.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// New - returns middleware that loads the session whose ID is presented with each request from store, extending its
+// TTL, and adds it to the request context before calling the next handler
+func New(store sessions.SessionStore, cfg Config) func(http.Handler) http.Handler {
+	if cfg.CookieName == "" {
+		cfg.CookieName = DefaultCookieName
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, fromCookie := cfg.sessionID(r)
+			if id == "" {
+				cfg.unauthenticated(next, w, r)
+				return
+			}
+
+			s, err := store.GetByIDContext(r.Context(), id)
+			if IsNotFound(err) {
+				cfg.unauthenticated(next, w, r)
+				return
+			}
+			if err != nil {
+				cfg.fail(w, r, err)
+				return
+			}
+
+			if cfg.Cookies != nil && cfg.Cookies.pastMaxLifetime(s) {
+				cfg.Cookies.Clear(w)
+				cfg.unauthenticated(next, w, r)
+				return
+			}
+
+			if cfg.Cookies != nil && fromCookie {
+				if err := cfg.Cookies.Write(w, s); err != nil {
+					cfg.fail(w, r, err)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), s)))
+		})
+	}
+}
+
+// NewContext - returns a copy of ctx holding the session
+func NewContext(ctx context.Context, s *sessions.Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// SessionFromContext - returns the session added to ctx by the middleware, and whether there is one
+func SessionFromContext(ctx context.Context) (*sessions.Session, bool) {
+	s, ok := ctx.Value(contextKey{}).(*sessions.Session)
+	return s, ok && s != nil
+}
+
+// IsNotFound - reports whether err is the error returned by a session store for a session that does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, sessions.ErrSessionNotFound)
+}
+
+// sessionID returns the session ID presented with the request, or an empty string if there is none or it is not
+// validly signed, and whether it was read from the session cookie
+func (cfg Config) sessionID(r *http.Request) (string, bool) {
+	if cfg.Cookies != nil {
+		id, err := cfg.Cookies.Read(r)
+		if err != ErrNoSessionCookie {
+			return id, true
+		}
+	} else if c, err := r.Cookie(cfg.CookieName); err == nil && c.Value != "" {
+		return c.Value, true
+	}
+
+	if cfg.HeaderName == "" {
+		return "", false
+	}
+
+	value := strings.TrimSpace(strings.TrimPrefix(r.Header.Get(cfg.HeaderName), "Bearer "))
+	if value == "" || cfg.Cookies == nil {
+		return value, false
+	}
+
+	id, err := cfg.Cookies.verify(value)
+	if err != nil {
+		return "", false
+	}
+
+	return id, false
+}
+
+// unauthenticated handles a request without a session
+func (cfg Config) unauthenticated(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	if cfg.OnUnauthenticated != nil {
+		cfg.OnUnauthenticated(w, r)
+		return
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// fail handles a request whose session could not be loaded
+func (cfg Config) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if cfg.OnError != nil {
+		cfg.OnError(w, r, err)
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,259 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// failingStore is a session store whose lookups fail with err
+type failingStore struct {
+	sessions.SessionStore
+	err error
+}
+
+func (f failingStore) GetByIDContext(ctx context.Context, id string) (*sessions.Session, error) {
+	return nil, f.err
+}
+
+// serve runs req through the middleware created with store and cfg, returning the response and the session the next
+// handler found in the context, if it was called
+func serve(store sessions.SessionStore, cfg Config, req *http.Request) (*httptest.ResponseRecorder, *sessions.Session, bool) {
+	var got *sessions.Session
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		got, _ = SessionFromContext(r.Context())
+	})
+
+	rec := httptest.NewRecorder()
+	New(store, cfg)(next).ServeHTTP(rec, req)
+
+	return rec, got, called
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given a store holding a session", t, func() {
+		store, err := sessions.NewMemoryStore(sessions.Config{TTL: time.Minute})
+		So(err, ShouldBeNil)
+		So(store.SetSessionContext(context.Background(), &sessions.Session{ID: "1234", Email: "user@email.com"}), ShouldBeNil)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		Convey("When a request presents the session ID in the default cookie", func() {
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "1234"})
+			_, s, called := serve(store, Config{}, req)
+
+			Convey("Then the next handler receives the session in the context", func() {
+				So(called, ShouldBeTrue)
+				So(s, ShouldNotBeNil)
+				So(s.ID, ShouldEqual, "1234")
+				So(s.Email, ShouldEqual, "user@email.com")
+			})
+		})
+
+		Convey("When a request presents the session ID in the configured header", func() {
+			req.Header.Set("Authorization", "Bearer 1234")
+			_, s, _ := serve(store, Config{HeaderName: "Authorization"}, req)
+
+			Convey("Then the session is loaded from it", func() {
+				So(s, ShouldNotBeNil)
+				So(s.ID, ShouldEqual, "1234")
+			})
+		})
+
+		Convey("When a request presents the session ID in a cookie with a custom name", func() {
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: "1234"})
+			_, s, _ := serve(store, Config{CookieName: "access_token"}, req)
+
+			Convey("Then the session is loaded from it", func() {
+				So(s, ShouldNotBeNil)
+				So(s.ID, ShouldEqual, "1234")
+			})
+		})
+
+		Convey("When a request presents an unknown session ID", func() {
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "5678"})
+
+			Convey("And there is no unauthenticated hook", func() {
+				_, s, called := serve(store, Config{}, req)
+
+				Convey("Then the next handler is called without a session", func() {
+					So(called, ShouldBeTrue)
+					So(s, ShouldBeNil)
+				})
+			})
+
+			Convey("And there is an unauthenticated hook", func() {
+				cfg := Config{OnUnauthenticated: func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				}}
+				rec, _, called := serve(store, cfg, req)
+
+				Convey("Then the hook handles the request instead of the next handler", func() {
+					So(called, ShouldBeFalse)
+					So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+				})
+			})
+		})
+
+		Convey("When a request presents no session ID", func() {
+			hooked := false
+			cfg := Config{OnUnauthenticated: func(w http.ResponseWriter, r *http.Request) { hooked = true }}
+			_, _, called := serve(store, cfg, req)
+
+			Convey("Then the unauthenticated hook is called", func() {
+				So(hooked, ShouldBeTrue)
+				So(called, ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given cookies with a max lifetime and a store holding sessions inside and past it", t, func() {
+		store := newStore(time.Hour)
+		cookies, err := NewCookies(store, CookieConfig{MaxLifetime: 8 * time.Hour})
+		So(err, ShouldBeNil)
+
+		ctx := context.Background()
+		So(store.SetSessionContext(ctx, &sessions.Session{ID: "fresh", Email: "fresh@email.com", Start: time.Now()}), ShouldBeNil)
+		So(store.SetSessionContext(ctx, &sessions.Session{ID: "old", Email: "old@email.com", Start: time.Now().Add(-9 * time.Hour)}), ShouldBeNil)
+
+		hooked := false
+		cfg := Config{Cookies: cookies, OnUnauthenticated: func(w http.ResponseWriter, r *http.Request) { hooked = true }}
+
+		Convey("When a request presents a session inside its max lifetime", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "fresh"})
+			_, s, called := serve(store, cfg, req)
+
+			Convey("Then the session is loaded", func() {
+				So(called, ShouldBeTrue)
+				So(s.ID, ShouldEqual, "fresh")
+			})
+		})
+
+		Convey("When a request presents a session past its max lifetime, however recently it was used", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "old"})
+			rec, _, called := serve(store, cfg, req)
+
+			Convey("Then the request is unauthenticated and the cookie is cleared", func() {
+				So(called, ShouldBeFalse)
+				So(hooked, ShouldBeTrue)
+				cookie := written(rec)
+				So(cookie.Value, ShouldBeEmpty)
+				So(cookie.MaxAge, ShouldEqual, -1)
+			})
+		})
+	})
+
+	Convey("Given cookies for a store whose sessions expire after a minute", t, func() {
+		now := time.Now()
+		store, err := sessions.NewMemoryStore(sessions.Config{TTL: time.Minute, Clock: func() time.Time { return now }})
+		So(err, ShouldBeNil)
+		So(store.SetSessionContext(context.Background(), &sessions.Session{ID: "1234", Email: "user@email.com", Start: now}), ShouldBeNil)
+
+		cookies, err := NewCookies(store, CookieConfig{})
+		So(err, ShouldBeNil)
+
+		Convey("When the session is used every 45 seconds for longer than three TTLs", func() {
+			var recs []*httptest.ResponseRecorder
+			for i := 0; i < 4; i++ {
+				now = now.Add(45 * time.Second)
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "1234"})
+				rec, s, _ := serve(store, Config{Cookies: cookies}, req)
+				So(s, ShouldNotBeNil)
+				recs = append(recs, rec)
+			}
+
+			Convey("Then every response refreshes the cookie for another TTL", func() {
+				for _, rec := range recs {
+					cookie := written(rec)
+					So(cookie.Value, ShouldEqual, "1234")
+					So(cookie.MaxAge, ShouldEqual, 60)
+				}
+			})
+		})
+
+		Convey("When the session is loaded from a header", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer 1234")
+			rec, s, _ := serve(store, Config{Cookies: cookies, HeaderName: "Authorization"}, req)
+
+			Convey("Then no cookie is written", func() {
+				So(s, ShouldNotBeNil)
+				So(rec.Result().Cookies(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a store that fails", t, func() {
+		store := failingStore{err: errors.New("connection refused")}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "1234"})
+
+		Convey("When a request is made without an error hook", func() {
+			rec, _, called := serve(store, Config{}, req)
+
+			Convey("Then a 500 is returned", func() {
+				So(called, ShouldBeFalse)
+				So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+
+		Convey("When a request is made with an error hook", func() {
+			var hookErr error
+			cfg := Config{OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+				hookErr = err
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}}
+			rec, _, _ := serve(store, cfg, req)
+
+			Convey("Then the hook receives the error", func() {
+				So(hookErr, ShouldEqual, store.err)
+				So(rec.Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+
+		Convey("When the store reports the session as not found", func() {
+			store.err = sessions.ErrSessionNotFound
+			_, s, called := serve(store, Config{}, req)
+
+			Convey("Then the request is unauthenticated rather than failed", func() {
+				So(called, ShouldBeTrue)
+				So(s, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestSessionFromContext(t *testing.T) {
+	Convey("Given a context without a session", t, func() {
+		ctx := context.Background()
+
+		Convey("When the session is requested", func() {
+			s, ok := SessionFromContext(ctx)
+
+			Convey("Then none is returned", func() {
+				So(ok, ShouldBeFalse)
+				So(s, ShouldBeNil)
+			})
+		})
+
+		Convey("When a session is added", func() {
+			s, ok := SessionFromContext(NewContext(ctx, &sessions.Session{ID: "1234"}))
+
+			Convey("Then it is returned", func() {
+				So(ok, ShouldBeTrue)
+				So(s.ID, ShouldEqual, "1234")
+			})
+		})
+	})
+}
@@ -10,11 +10,25 @@ import (
 )
 
 var (
-	lockRedisClienterMockExpire   sync.RWMutex
-	lockRedisClienterMockFlushAll sync.RWMutex
-	lockRedisClienterMockGet      sync.RWMutex
-	lockRedisClienterMockPing     sync.RWMutex
-	lockRedisClienterMockSet      sync.RWMutex
+	lockRedisClienterMockClose        sync.RWMutex
+	lockRedisClienterMockDel          sync.RWMutex
+	lockRedisClienterMockEval         sync.RWMutex
+	lockRedisClienterMockEvalSha      sync.RWMutex
+	lockRedisClienterMockExpire       sync.RWMutex
+	lockRedisClienterMockFlushAll     sync.RWMutex
+	lockRedisClienterMockGet          sync.RWMutex
+	lockRedisClienterMockPTTL         sync.RWMutex
+	lockRedisClienterMockPing         sync.RWMutex
+	lockRedisClienterMockPoolStats    sync.RWMutex
+	lockRedisClienterMockPublish      sync.RWMutex
+	lockRedisClienterMockScan         sync.RWMutex
+	lockRedisClienterMockScriptExists sync.RWMutex
+	lockRedisClienterMockScriptLoad   sync.RWMutex
+	lockRedisClienterMockSet          sync.RWMutex
+	lockRedisClienterMockSubscribe    sync.RWMutex
+	lockRedisClienterMockZAdd         sync.RWMutex
+	lockRedisClienterMockZCount       sync.RWMutex
+	lockRedisClienterMockZRem         sync.RWMutex
 )
 
 // Ensure, that RedisClienterMock does implement RedisClienter.
@@ -27,6 +41,18 @@ var _ RedisClienter = &RedisClienterMock{}
 //
 //         // make and configure a mocked RedisClienter
 //         mockedRedisClienter := &RedisClienterMock{
+//             CloseFunc: func() error {
+// 	               panic("mock out the Close method")
+//             },
+//             DelFunc: func(keys ...string) *redis.IntCmd {
+// 	               panic("mock out the Del method")
+//             },
+//             EvalFunc: func(script string, keys []string, args ...interface{}) *redis.Cmd {
+// 	               panic("mock out the Eval method")
+//             },
+//             EvalShaFunc: func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+// 	               panic("mock out the EvalSha method")
+//             },
 //             ExpireFunc: func(key string, expiration time.Duration) *redis.BoolCmd {
 // 	               panic("mock out the Expire method")
 //             },
@@ -36,12 +62,42 @@ var _ RedisClienter = &RedisClienterMock{}
 //             GetFunc: func(key string) *redis.StringCmd {
 // 	               panic("mock out the Get method")
 //             },
+//             PTTLFunc: func(key string) *redis.DurationCmd {
+// 	               panic("mock out the PTTL method")
+//             },
 //             PingFunc: func() *redis.StatusCmd {
 // 	               panic("mock out the Ping method")
 //             },
+//             PoolStatsFunc: func() *redis.PoolStats {
+// 	               panic("mock out the PoolStats method")
+//             },
+//             PublishFunc: func(channel string, message interface{}) *redis.IntCmd {
+// 	               panic("mock out the Publish method")
+//             },
+//             ScanFunc: func(cursor uint64, match string, count int64) *redis.ScanCmd {
+// 	               panic("mock out the Scan method")
+//             },
+//             ScriptExistsFunc: func(hashes ...string) *redis.BoolSliceCmd {
+// 	               panic("mock out the ScriptExists method")
+//             },
+//             ScriptLoadFunc: func(script string) *redis.StringCmd {
+// 	               panic("mock out the ScriptLoad method")
+//             },
 //             SetFunc: func(key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
 // 	               panic("mock out the Set method")
 //             },
+//             SubscribeFunc: func(channels ...string) PubSub {
+// 	               panic("mock out the Subscribe method")
+//             },
+//             ZAddFunc: func(key string, members ...redis.Z) *redis.IntCmd {
+// 	               panic("mock out the ZAdd method")
+//             },
+//             ZCountFunc: func(key string, min string, max string) *redis.IntCmd {
+// 	               panic("mock out the ZCount method")
+//             },
+//             ZRemFunc: func(key string, members ...interface{}) *redis.IntCmd {
+// 	               panic("mock out the ZRem method")
+//             },
 //         }
 //
 //         // use mockedRedisClienter in code that requires RedisClienter
@@ -49,6 +105,18 @@ var _ RedisClienter = &RedisClienterMock{}
 //
 //     }
 type RedisClienterMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func() error
+
+	// DelFunc mocks the Del method.
+	DelFunc func(keys ...string) *redis.IntCmd
+
+	// EvalFunc mocks the Eval method.
+	EvalFunc func(script string, keys []string, args ...interface{}) *redis.Cmd
+
+	// EvalShaFunc mocks the EvalSha method.
+	EvalShaFunc func(sha1 string, keys []string, args ...interface{}) *redis.Cmd
+
 	// ExpireFunc mocks the Expire method.
 	ExpireFunc func(key string, expiration time.Duration) *redis.BoolCmd
 
@@ -58,14 +126,70 @@ type RedisClienterMock struct {
 	// GetFunc mocks the Get method.
 	GetFunc func(key string) *redis.StringCmd
 
+	// PTTLFunc mocks the PTTL method.
+	PTTLFunc func(key string) *redis.DurationCmd
+
 	// PingFunc mocks the Ping method.
 	PingFunc func() *redis.StatusCmd
 
+	// PoolStatsFunc mocks the PoolStats method.
+	PoolStatsFunc func() *redis.PoolStats
+
+	// PublishFunc mocks the Publish method.
+	PublishFunc func(channel string, message interface{}) *redis.IntCmd
+
+	// ScanFunc mocks the Scan method.
+	ScanFunc func(cursor uint64, match string, count int64) *redis.ScanCmd
+
+	// ScriptExistsFunc mocks the ScriptExists method.
+	ScriptExistsFunc func(hashes ...string) *redis.BoolSliceCmd
+
+	// ScriptLoadFunc mocks the ScriptLoad method.
+	ScriptLoadFunc func(script string) *redis.StringCmd
+
 	// SetFunc mocks the Set method.
 	SetFunc func(key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 
+	// SubscribeFunc mocks the Subscribe method.
+	SubscribeFunc func(channels ...string) PubSub
+
+	// ZAddFunc mocks the ZAdd method.
+	ZAddFunc func(key string, members ...redis.Z) *redis.IntCmd
+
+	// ZCountFunc mocks the ZCount method.
+	ZCountFunc func(key string, min string, max string) *redis.IntCmd
+
+	// ZRemFunc mocks the ZRem method.
+	ZRemFunc func(key string, members ...interface{}) *redis.IntCmd
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+		}
+		// Del holds details about calls to the Del method.
+		Del []struct {
+			// Keys is the keys argument value.
+			Keys []string
+		}
+		// Eval holds details about calls to the Eval method.
+		Eval []struct {
+			// Script is the script argument value.
+			Script string
+			// Keys is the keys argument value.
+			Keys []string
+			// Args is the args argument value.
+			Args []interface{}
+		}
+		// EvalSha holds details about calls to the EvalSha method.
+		EvalSha []struct {
+			// Sha1 is the sha1 argument value.
+			Sha1 string
+			// Keys is the keys argument value.
+			Keys []string
+			// Args is the args argument value.
+			Args []interface{}
+		}
 		// Expire holds details about calls to the Expire method.
 		Expire []struct {
 			// Key is the key argument value.
@@ -81,9 +205,43 @@ type RedisClienterMock struct {
 			// Key is the key argument value.
 			Key string
 		}
+		// PTTL holds details about calls to the PTTL method.
+		PTTL []struct {
+			// Key is the key argument value.
+			Key string
+		}
 		// Ping holds details about calls to the Ping method.
 		Ping []struct {
 		}
+		// PoolStats holds details about calls to the PoolStats method.
+		PoolStats []struct {
+		}
+		// Publish holds details about calls to the Publish method.
+		Publish []struct {
+			// Channel is the channel argument value.
+			Channel string
+			// Message is the message argument value.
+			Message interface{}
+		}
+		// Scan holds details about calls to the Scan method.
+		Scan []struct {
+			// Cursor is the cursor argument value.
+			Cursor uint64
+			// Match is the match argument value.
+			Match string
+			// Count is the count argument value.
+			Count int64
+		}
+		// ScriptExists holds details about calls to the ScriptExists method.
+		ScriptExists []struct {
+			// Hashes is the hashes argument value.
+			Hashes []string
+		}
+		// ScriptLoad holds details about calls to the ScriptLoad method.
+		ScriptLoad []struct {
+			// Script is the script argument value.
+			Script string
+		}
 		// Set holds details about calls to the Set method.
 		Set []struct {
 			// Key is the key argument value.
@@ -93,9 +251,172 @@ type RedisClienterMock struct {
 			// Expiration is the expiration argument value.
 			Expiration time.Duration
 		}
+		// Subscribe holds details about calls to the Subscribe method.
+		Subscribe []struct {
+			// Channels is the channels argument value.
+			Channels []string
+		}
+		// ZAdd holds details about calls to the ZAdd method.
+		ZAdd []struct {
+			// Key is the key argument value.
+			Key string
+			// Members is the members argument value.
+			Members []redis.Z
+		}
+		// ZCount holds details about calls to the ZCount method.
+		ZCount []struct {
+			// Key is the key argument value.
+			Key string
+			// Min is the min argument value.
+			Min string
+			// Max is the max argument value.
+			Max string
+		}
+		// ZRem holds details about calls to the ZRem method.
+		ZRem []struct {
+			// Key is the key argument value.
+			Key string
+			// Members is the members argument value.
+			Members []interface{}
+		}
 	}
 }
 
+// Close calls CloseFunc.
+func (mock *RedisClienterMock) Close() error {
+	if mock.CloseFunc == nil {
+		panic("RedisClienterMock.CloseFunc: method is nil but RedisClienter.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockRedisClienterMockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	lockRedisClienterMockClose.Unlock()
+	return mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//     len(mockedRedisClienter.CloseCalls())
+func (mock *RedisClienterMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockRedisClienterMockClose.RLock()
+	calls = mock.calls.Close
+	lockRedisClienterMockClose.RUnlock()
+	return calls
+}
+
+// Del calls DelFunc.
+func (mock *RedisClienterMock) Del(keys ...string) *redis.IntCmd {
+	if mock.DelFunc == nil {
+		panic("RedisClienterMock.DelFunc: method is nil but RedisClienter.Del was just called")
+	}
+	callInfo := struct {
+		Keys []string
+	}{
+		Keys: keys,
+	}
+	lockRedisClienterMockDel.Lock()
+	mock.calls.Del = append(mock.calls.Del, callInfo)
+	lockRedisClienterMockDel.Unlock()
+	return mock.DelFunc(keys...)
+}
+
+// DelCalls gets all the calls that were made to Del.
+// Check the length with:
+//     len(mockedRedisClienter.DelCalls())
+func (mock *RedisClienterMock) DelCalls() []struct {
+	Keys []string
+} {
+	var calls []struct {
+		Keys []string
+	}
+	lockRedisClienterMockDel.RLock()
+	calls = mock.calls.Del
+	lockRedisClienterMockDel.RUnlock()
+	return calls
+}
+
+// Eval calls EvalFunc.
+func (mock *RedisClienterMock) Eval(script string, keys []string, args ...interface{}) *redis.Cmd {
+	if mock.EvalFunc == nil {
+		panic("RedisClienterMock.EvalFunc: method is nil but RedisClienter.Eval was just called")
+	}
+	callInfo := struct {
+		Script string
+		Keys   []string
+		Args   []interface{}
+	}{
+		Script: script,
+		Keys:   keys,
+		Args:   args,
+	}
+	lockRedisClienterMockEval.Lock()
+	mock.calls.Eval = append(mock.calls.Eval, callInfo)
+	lockRedisClienterMockEval.Unlock()
+	return mock.EvalFunc(script, keys, args...)
+}
+
+// EvalCalls gets all the calls that were made to Eval.
+// Check the length with:
+//     len(mockedRedisClienter.EvalCalls())
+func (mock *RedisClienterMock) EvalCalls() []struct {
+	Script string
+	Keys   []string
+	Args   []interface{}
+} {
+	var calls []struct {
+		Script string
+		Keys   []string
+		Args   []interface{}
+	}
+	lockRedisClienterMockEval.RLock()
+	calls = mock.calls.Eval
+	lockRedisClienterMockEval.RUnlock()
+	return calls
+}
+
+// EvalSha calls EvalShaFunc.
+func (mock *RedisClienterMock) EvalSha(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+	if mock.EvalShaFunc == nil {
+		panic("RedisClienterMock.EvalShaFunc: method is nil but RedisClienter.EvalSha was just called")
+	}
+	callInfo := struct {
+		Sha1 string
+		Keys []string
+		Args []interface{}
+	}{
+		Sha1: sha1,
+		Keys: keys,
+		Args: args,
+	}
+	lockRedisClienterMockEvalSha.Lock()
+	mock.calls.EvalSha = append(mock.calls.EvalSha, callInfo)
+	lockRedisClienterMockEvalSha.Unlock()
+	return mock.EvalShaFunc(sha1, keys, args...)
+}
+
+// EvalShaCalls gets all the calls that were made to EvalSha.
+// Check the length with:
+//     len(mockedRedisClienter.EvalShaCalls())
+func (mock *RedisClienterMock) EvalShaCalls() []struct {
+	Sha1 string
+	Keys []string
+	Args []interface{}
+} {
+	var calls []struct {
+		Sha1 string
+		Keys []string
+		Args []interface{}
+	}
+	lockRedisClienterMockEvalSha.RLock()
+	calls = mock.calls.EvalSha
+	lockRedisClienterMockEvalSha.RUnlock()
+	return calls
+}
+
 // Expire calls ExpireFunc.
 func (mock *RedisClienterMock) Expire(key string, expiration time.Duration) *redis.BoolCmd {
 	if mock.ExpireFunc == nil {
@@ -188,6 +509,37 @@ func (mock *RedisClienterMock) GetCalls() []struct {
 	return calls
 }
 
+// PTTL calls PTTLFunc.
+func (mock *RedisClienterMock) PTTL(key string) *redis.DurationCmd {
+	if mock.PTTLFunc == nil {
+		panic("RedisClienterMock.PTTLFunc: method is nil but RedisClienter.PTTL was just called")
+	}
+	callInfo := struct {
+		Key string
+	}{
+		Key: key,
+	}
+	lockRedisClienterMockPTTL.Lock()
+	mock.calls.PTTL = append(mock.calls.PTTL, callInfo)
+	lockRedisClienterMockPTTL.Unlock()
+	return mock.PTTLFunc(key)
+}
+
+// PTTLCalls gets all the calls that were made to PTTL.
+// Check the length with:
+//     len(mockedRedisClienter.PTTLCalls())
+func (mock *RedisClienterMock) PTTLCalls() []struct {
+	Key string
+} {
+	var calls []struct {
+		Key string
+	}
+	lockRedisClienterMockPTTL.RLock()
+	calls = mock.calls.PTTL
+	lockRedisClienterMockPTTL.RUnlock()
+	return calls
+}
+
 // Ping calls PingFunc.
 func (mock *RedisClienterMock) Ping() *redis.StatusCmd {
 	if mock.PingFunc == nil {
@@ -214,6 +566,168 @@ func (mock *RedisClienterMock) PingCalls() []struct {
 	return calls
 }
 
+// PoolStats calls PoolStatsFunc.
+func (mock *RedisClienterMock) PoolStats() *redis.PoolStats {
+	if mock.PoolStatsFunc == nil {
+		panic("RedisClienterMock.PoolStatsFunc: method is nil but RedisClienter.PoolStats was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockRedisClienterMockPoolStats.Lock()
+	mock.calls.PoolStats = append(mock.calls.PoolStats, callInfo)
+	lockRedisClienterMockPoolStats.Unlock()
+	return mock.PoolStatsFunc()
+}
+
+// PoolStatsCalls gets all the calls that were made to PoolStats.
+// Check the length with:
+//     len(mockedRedisClienter.PoolStatsCalls())
+func (mock *RedisClienterMock) PoolStatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockRedisClienterMockPoolStats.RLock()
+	calls = mock.calls.PoolStats
+	lockRedisClienterMockPoolStats.RUnlock()
+	return calls
+}
+
+// Publish calls PublishFunc.
+func (mock *RedisClienterMock) Publish(channel string, message interface{}) *redis.IntCmd {
+	if mock.PublishFunc == nil {
+		panic("RedisClienterMock.PublishFunc: method is nil but RedisClienter.Publish was just called")
+	}
+	callInfo := struct {
+		Channel string
+		Message interface{}
+	}{
+		Channel: channel,
+		Message: message,
+	}
+	lockRedisClienterMockPublish.Lock()
+	mock.calls.Publish = append(mock.calls.Publish, callInfo)
+	lockRedisClienterMockPublish.Unlock()
+	return mock.PublishFunc(channel, message)
+}
+
+// PublishCalls gets all the calls that were made to Publish.
+// Check the length with:
+//     len(mockedRedisClienter.PublishCalls())
+func (mock *RedisClienterMock) PublishCalls() []struct {
+	Channel string
+	Message interface{}
+} {
+	var calls []struct {
+		Channel string
+		Message interface{}
+	}
+	lockRedisClienterMockPublish.RLock()
+	calls = mock.calls.Publish
+	lockRedisClienterMockPublish.RUnlock()
+	return calls
+}
+
+// Scan calls ScanFunc.
+func (mock *RedisClienterMock) Scan(cursor uint64, match string, count int64) *redis.ScanCmd {
+	if mock.ScanFunc == nil {
+		panic("RedisClienterMock.ScanFunc: method is nil but RedisClienter.Scan was just called")
+	}
+	callInfo := struct {
+		Cursor uint64
+		Match  string
+		Count  int64
+	}{
+		Cursor: cursor,
+		Match:  match,
+		Count:  count,
+	}
+	lockRedisClienterMockScan.Lock()
+	mock.calls.Scan = append(mock.calls.Scan, callInfo)
+	lockRedisClienterMockScan.Unlock()
+	return mock.ScanFunc(cursor, match, count)
+}
+
+// ScanCalls gets all the calls that were made to Scan.
+// Check the length with:
+//     len(mockedRedisClienter.ScanCalls())
+func (mock *RedisClienterMock) ScanCalls() []struct {
+	Cursor uint64
+	Match  string
+	Count  int64
+} {
+	var calls []struct {
+		Cursor uint64
+		Match  string
+		Count  int64
+	}
+	lockRedisClienterMockScan.RLock()
+	calls = mock.calls.Scan
+	lockRedisClienterMockScan.RUnlock()
+	return calls
+}
+
+// ScriptExists calls ScriptExistsFunc.
+func (mock *RedisClienterMock) ScriptExists(hashes ...string) *redis.BoolSliceCmd {
+	if mock.ScriptExistsFunc == nil {
+		panic("RedisClienterMock.ScriptExistsFunc: method is nil but RedisClienter.ScriptExists was just called")
+	}
+	callInfo := struct {
+		Hashes []string
+	}{
+		Hashes: hashes,
+	}
+	lockRedisClienterMockScriptExists.Lock()
+	mock.calls.ScriptExists = append(mock.calls.ScriptExists, callInfo)
+	lockRedisClienterMockScriptExists.Unlock()
+	return mock.ScriptExistsFunc(hashes...)
+}
+
+// ScriptExistsCalls gets all the calls that were made to ScriptExists.
+// Check the length with:
+//     len(mockedRedisClienter.ScriptExistsCalls())
+func (mock *RedisClienterMock) ScriptExistsCalls() []struct {
+	Hashes []string
+} {
+	var calls []struct {
+		Hashes []string
+	}
+	lockRedisClienterMockScriptExists.RLock()
+	calls = mock.calls.ScriptExists
+	lockRedisClienterMockScriptExists.RUnlock()
+	return calls
+}
+
+// ScriptLoad calls ScriptLoadFunc.
+func (mock *RedisClienterMock) ScriptLoad(script string) *redis.StringCmd {
+	if mock.ScriptLoadFunc == nil {
+		panic("RedisClienterMock.ScriptLoadFunc: method is nil but RedisClienter.ScriptLoad was just called")
+	}
+	callInfo := struct {
+		Script string
+	}{
+		Script: script,
+	}
+	lockRedisClienterMockScriptLoad.Lock()
+	mock.calls.ScriptLoad = append(mock.calls.ScriptLoad, callInfo)
+	lockRedisClienterMockScriptLoad.Unlock()
+	return mock.ScriptLoadFunc(script)
+}
+
+// ScriptLoadCalls gets all the calls that were made to ScriptLoad.
+// Check the length with:
+//     len(mockedRedisClienter.ScriptLoadCalls())
+func (mock *RedisClienterMock) ScriptLoadCalls() []struct {
+	Script string
+} {
+	var calls []struct {
+		Script string
+	}
+	lockRedisClienterMockScriptLoad.RLock()
+	calls = mock.calls.ScriptLoad
+	lockRedisClienterMockScriptLoad.RUnlock()
+	return calls
+}
+
 // Set calls SetFunc.
 func (mock *RedisClienterMock) Set(key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
 	if mock.SetFunc == nil {
@@ -252,3 +766,143 @@ func (mock *RedisClienterMock) SetCalls() []struct {
 	lockRedisClienterMockSet.RUnlock()
 	return calls
 }
+
+// Subscribe calls SubscribeFunc.
+func (mock *RedisClienterMock) Subscribe(channels ...string) PubSub {
+	if mock.SubscribeFunc == nil {
+		panic("RedisClienterMock.SubscribeFunc: method is nil but RedisClienter.Subscribe was just called")
+	}
+	callInfo := struct {
+		Channels []string
+	}{
+		Channels: channels,
+	}
+	lockRedisClienterMockSubscribe.Lock()
+	mock.calls.Subscribe = append(mock.calls.Subscribe, callInfo)
+	lockRedisClienterMockSubscribe.Unlock()
+	return mock.SubscribeFunc(channels...)
+}
+
+// SubscribeCalls gets all the calls that were made to Subscribe.
+// Check the length with:
+//     len(mockedRedisClienter.SubscribeCalls())
+func (mock *RedisClienterMock) SubscribeCalls() []struct {
+	Channels []string
+} {
+	var calls []struct {
+		Channels []string
+	}
+	lockRedisClienterMockSubscribe.RLock()
+	calls = mock.calls.Subscribe
+	lockRedisClienterMockSubscribe.RUnlock()
+	return calls
+}
+
+// ZAdd calls ZAddFunc.
+func (mock *RedisClienterMock) ZAdd(key string, members ...redis.Z) *redis.IntCmd {
+	if mock.ZAddFunc == nil {
+		panic("RedisClienterMock.ZAddFunc: method is nil but RedisClienter.ZAdd was just called")
+	}
+	callInfo := struct {
+		Key     string
+		Members []redis.Z
+	}{
+		Key:     key,
+		Members: members,
+	}
+	lockRedisClienterMockZAdd.Lock()
+	mock.calls.ZAdd = append(mock.calls.ZAdd, callInfo)
+	lockRedisClienterMockZAdd.Unlock()
+	return mock.ZAddFunc(key, members...)
+}
+
+// ZAddCalls gets all the calls that were made to ZAdd.
+// Check the length with:
+//     len(mockedRedisClienter.ZAddCalls())
+func (mock *RedisClienterMock) ZAddCalls() []struct {
+	Key     string
+	Members []redis.Z
+} {
+	var calls []struct {
+		Key     string
+		Members []redis.Z
+	}
+	lockRedisClienterMockZAdd.RLock()
+	calls = mock.calls.ZAdd
+	lockRedisClienterMockZAdd.RUnlock()
+	return calls
+}
+
+// ZCount calls ZCountFunc.
+func (mock *RedisClienterMock) ZCount(key string, min string, max string) *redis.IntCmd {
+	if mock.ZCountFunc == nil {
+		panic("RedisClienterMock.ZCountFunc: method is nil but RedisClienter.ZCount was just called")
+	}
+	callInfo := struct {
+		Key string
+		Min string
+		Max string
+	}{
+		Key: key,
+		Min: min,
+		Max: max,
+	}
+	lockRedisClienterMockZCount.Lock()
+	mock.calls.ZCount = append(mock.calls.ZCount, callInfo)
+	lockRedisClienterMockZCount.Unlock()
+	return mock.ZCountFunc(key, min, max)
+}
+
+// ZCountCalls gets all the calls that were made to ZCount.
+// Check the length with:
+//     len(mockedRedisClienter.ZCountCalls())
+func (mock *RedisClienterMock) ZCountCalls() []struct {
+	Key string
+	Min string
+	Max string
+} {
+	var calls []struct {
+		Key string
+		Min string
+		Max string
+	}
+	lockRedisClienterMockZCount.RLock()
+	calls = mock.calls.ZCount
+	lockRedisClienterMockZCount.RUnlock()
+	return calls
+}
+
+// ZRem calls ZRemFunc.
+func (mock *RedisClienterMock) ZRem(key string, members ...interface{}) *redis.IntCmd {
+	if mock.ZRemFunc == nil {
+		panic("RedisClienterMock.ZRemFunc: method is nil but RedisClienter.ZRem was just called")
+	}
+	callInfo := struct {
+		Key     string
+		Members []interface{}
+	}{
+		Key:     key,
+		Members: members,
+	}
+	lockRedisClienterMockZRem.Lock()
+	mock.calls.ZRem = append(mock.calls.ZRem, callInfo)
+	lockRedisClienterMockZRem.Unlock()
+	return mock.ZRemFunc(key, members...)
+}
+
+// ZRemCalls gets all the calls that were made to ZRem.
+// Check the length with:
+//     len(mockedRedisClienter.ZRemCalls())
+func (mock *RedisClienterMock) ZRemCalls() []struct {
+	Key     string
+	Members []interface{}
+} {
+	var calls []struct {
+		Key     string
+		Members []interface{}
+	}
+	lockRedisClienterMockZRem.RLock()
+	calls = mock.calls.ZRem
+	lockRedisClienterMockZRem.RUnlock()
+	return calls
+}
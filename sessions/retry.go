@@ -0,0 +1,116 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryPolicy - config options for retrying operations that fail with a transient error
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times an operation is attempted, including the first
+	MaxAttempts int
+
+	// InitialBackoff is the upper bound of the wait before the first retry. It doubles for each retry after that.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the upper bound of the wait between retries
+	MaxBackoff time.Duration
+}
+
+// backoff returns how long to wait before the given retry, chosen at random up to an exponentially increasing bound
+// so that clients retrying at the same time spread out
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	bound := p.InitialBackoff
+	for i := 1; i < retry && bound < p.MaxBackoff; i++ {
+		bound *= 2
+	}
+
+	if p.MaxBackoff > 0 && bound > p.MaxBackoff {
+		bound = p.MaxBackoff
+	}
+
+	if bound <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(bound)))
+}
+
+// IsRetryable - reports whether err is a transient error that may succeed if the operation is retried, such as a
+// network error or redis still loading its dataset or failing over. Errors such as redis.Nil, ErrUnavailable and
+// errors in the content of a session are permanent.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrUnavailable) {
+		return false
+	}
+
+	if isConnectionError(err) {
+		return true
+	}
+
+	msg := rootCause(err).Error()
+	return strings.HasPrefix(msg, "READONLY ") ||
+		strings.HasPrefix(msg, "TRYAGAIN ") ||
+		strings.HasPrefix(msg, "CLUSTERDOWN ") ||
+		strings.HasPrefix(msg, "MASTERDOWN ") ||
+		msg == "ERR max number of clients reached"
+}
+
+// do runs fn, which makes calls to redis, through the circuit breaker if one is configured. If a retry policy is
+// configured and fn is idempotent, fn is retried after transient errors for as long as the policy and ctx allow.
+func (c *Client) do(ctx context.Context, idempotent bool, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	attempts := 1
+	if idempotent && c.retry != nil && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.attempt(fn)
+		if attempt >= attempts || !IsRetryable(err) {
+			return err
+		}
+
+		wait := c.retry.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+
+		c.logRetry(ctx, attempt+1, wait, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attrRetryAttempt.Int(attempt+1)))
+	}
+}
+
+// attempt runs fn once through the circuit breaker, if one is configured
+func (c *Client) attempt(fn func() error) error {
+	if c.breaker == nil {
+		return fn()
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	c.breaker.record(err)
+
+	return err
+}
@@ -0,0 +1,129 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIsRetryable(t *testing.T) {
+	Convey("Given errors returned from redis", t, func() {
+
+		Convey("Then transient errors are retryable", func() {
+			So(IsRetryable(io.EOF), ShouldBeTrue)
+			So(IsRetryable(errConnRefused), ShouldBeTrue)
+			So(IsRetryable(errors.New("redis: connection pool timeout")), ShouldBeTrue)
+			So(IsRetryable(errors.New("LOADING Redis is loading the dataset in memory")), ShouldBeTrue)
+			So(IsRetryable(errors.New("READONLY You can't write against a read only replica.")), ShouldBeTrue)
+			So(IsRetryable(errors.New("ERR max number of clients reached")), ShouldBeTrue)
+			So(IsRetryable(fmt.Errorf("redis client.Set returned an unexpected error: %w", errors.New("READONLY replica"))), ShouldBeTrue)
+		})
+
+		Convey("Then permanent errors are not retryable", func() {
+			So(IsRetryable(nil), ShouldBeFalse)
+			So(IsRetryable(redis.Nil), ShouldBeFalse)
+			So(IsRetryable(ErrUnavailable), ShouldBeFalse)
+			So(IsRetryable(context.DeadlineExceeded), ShouldBeFalse)
+			So(IsRetryable(errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")), ShouldBeFalse)
+			So(IsRetryable(errors.New("unexpected end of JSON input")), ShouldBeFalse)
+		})
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	Convey("Given a retry policy", t, func() {
+		p := &RetryPolicy{MaxAttempts: 5, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 30 * time.Millisecond}
+
+		Convey("Then the backoff is jittered within an exponentially increasing bound capped at the max", func() {
+			for i := 0; i < 100; i++ {
+				So(p.backoff(1), ShouldBeLessThan, 10*time.Millisecond)
+				So(p.backoff(2), ShouldBeLessThan, 20*time.Millisecond)
+				So(p.backoff(3), ShouldBeLessThan, 30*time.Millisecond)
+				So(p.backoff(10), ShouldBeLessThan, 30*time.Millisecond)
+			}
+		})
+	})
+}
+
+func TestClient_Retry(t *testing.T) {
+	Convey("Given a client with a retry policy and redis fails twice before recovering", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.PingFunc = func() *redis.StatusCmd {
+			if len(mockRedisClient.PingCalls()) < 3 {
+				return redis.NewStatusResult("", io.EOF)
+			}
+			return redis.NewStatusResult("PONG", nil)
+		}
+		mockRedisClient.PublishFunc = func(channel string, message interface{}) *redis.IntCmd {
+			return redis.NewIntResult(0, io.EOF)
+		}
+		client.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+		Convey("When an idempotent operation is called", func() {
+			err := client.PingContext(context.Background())
+
+			Convey("Then it is retried until it succeeds", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.PingCalls(), ShouldHaveLength, 3)
+			})
+		})
+
+		Convey("When an operation that is not safe to retry fails", func() {
+			client.events = true
+			err := client.SetSessionContext(context.Background(), &Session{ID: "1234", Email: "user@email.com"})
+
+			Convey("Then it is only attempted once", func() {
+				So(err, ShouldNotBeNil)
+				So(mockRedisClient.PublishCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the context deadline is too soon to wait for a retry", func() {
+			client.retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := client.PingContext(ctx)
+
+			Convey("Then the error is returned without waiting", func() {
+				So(err, ShouldEqual, io.EOF)
+				So(time.Since(start), ShouldBeLessThan, time.Second)
+			})
+		})
+
+		Convey("When the error is permanent", func() {
+			mockRedisClient.PingFunc = func() *redis.StatusCmd {
+				return redis.NewStatusResult("", errors.New("NOAUTH Authentication required."))
+			}
+			err := client.PingContext(context.Background())
+
+			Convey("Then it is not retried", func() {
+				So(err, ShouldNotBeNil)
+				So(mockRedisClient.PingCalls(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the context has already been cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := client.PingContext(ctx)
+
+			Convey("Then redis is not called", func() {
+				So(err, ShouldEqual, context.Canceled)
+				So(mockRedisClient.PingCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+}
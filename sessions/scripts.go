@@ -0,0 +1,156 @@
+package sessions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// getAndRefreshScript and pruneIndexScript read and write keys that are not passed in KEYS: the ID and email keys
+// named in a session's payload, and the session keys of the IDs in the expiry index. Redis only supports this on a
+// single instance, so the client does not support Redis Cluster, where those keys can live on different nodes.
+
+// peekScript returns the payload and remaining TTL in milliseconds of every key in KEYS, in order, as a flat array.
+// Missing keys are returned as a nil payload with a TTL of -2, matching PTTL, and keys that do not hold a string are
+// returned as a nil payload.
+var peekScript = redis.NewScript(`
+local res = {}
+for i, key in ipairs(KEYS) do
+	if redis.call('TYPE', key).ok == 'string' then
+		res[#res + 1] = redis.call('GET', key)
+	else
+		res[#res + 1] = false
+	end
+	res[#res + 1] = redis.call('PTTL', key)
+end
+return res
+`)
+
+// getAndRefreshScript returns the payload stored at KEYS[1] and its remaining TTL in milliseconds. If the remaining TTL
+// is no more than ARGV[4] milliseconds, the session is refreshed: its last_accessed field is set to ARGV[5] and it is
+// written back with an expiry of ARGV[1] milliseconds to its ID and email keys, which are not in KEYS, where they
+// still hold the session as it was read, and the new expiry of ARGV[2] (unix milliseconds) is recorded in the expiry
+// index at KEYS[2]. Keys that no longer hold the session, such as an email key taken over by a newer session, are left
+// alone. If ARGV[3] is not empty a refreshed event is published on that channel. The payload is returned as-is if it
+// cannot be decoded so the caller can report the error.
+var getAndRefreshScript = redis.NewScript(`
+local payload = redis.call('GET', KEYS[1])
+if not payload then
+	return false
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > tonumber(ARGV[4]) then
+	return {payload, ttl}
+end
+local ok, session = pcall(cjson.decode, payload)
+if ok and type(session) == 'table' then
+	local read = payload
+	session.last_accessed = ARGV[5]
+	payload = cjson.encode(session)
+	if type(session.id) == 'string' and session.id ~= '' and redis.call('GET', session.id) == read then
+		redis.call('SET', session.id, payload, 'PX', ARGV[1])
+		redis.call('ZADD', KEYS[2], ARGV[2], session.id)
+		if ARGV[3] ~= '' then
+			redis.call('PUBLISH', ARGV[3], cjson.encode({type = 'refreshed', id = session.id}))
+		end
+	end
+	if type(session.email) == 'string' and session.email ~= '' and redis.call('GET', session.email) == read then
+		redis.call('SET', session.email, payload, 'PX', ARGV[1])
+	end
+	ttl = tonumber(ARGV[1])
+end
+return {payload, ttl}
+`)
+
+// deleteScript removes the session stored under the ID key at KEYS[1] and its entry in the expiry index at KEYS[3].
+// The email key at KEYS[2] is only removed if it still holds the session, so that deleting an older session for the
+// same user leaves the email key of a newer one alone. It returns the number of keys removed.
+var deleteScript = redis.NewScript(`
+local removed = redis.call('DEL', KEYS[1])
+if redis.call('TYPE', KEYS[2]).ok == 'string' then
+	local ok, session = pcall(cjson.decode, redis.call('GET', KEYS[2]))
+	if ok and type(session) == 'table' and session.id == KEYS[1] then
+		removed = removed + redis.call('DEL', KEYS[2])
+	end
+end
+redis.call('ZREM', KEYS[3], KEYS[1])
+return removed
+`)
+
+// rotateScript moves the session stored under the ID key at KEYS[4] to the ID key at KEYS[1], storing the payload in
+// ARGV[1] with a TTL of ARGV[2] milliseconds under it and the email key at KEYS[2], and moving its entry in the expiry
+// index at KEYS[3] to the new ID with an expiry of ARGV[3] (unix milliseconds). The new ID key is only written if it does
+// not exist, so another session is never replaced. It returns 1 once the session has been moved, including when a retry
+// finds it already moved, 0 if the new ID is in use and -1 if the session no longer exists.
+var rotateScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'string' and redis.call('GET', KEYS[1]) == ARGV[1] then
+	return 1
+end
+if redis.call('EXISTS', KEYS[4]) == 0 then
+	return -1
+end
+if not redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2], 'NX') then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[2])
+redis.call('ZADD', KEYS[3], ARGV[3], KEYS[1])
+redis.call('DEL', KEYS[4])
+redis.call('ZREM', KEYS[3], KEYS[4])
+return 1
+`)
+
+// pruneIndexScript reconciles up to ARGV[2] entries in the expiry index at KEYS[1] whose recorded expiry is at or
+// before ARGV[1] (unix milliseconds). Entries whose session key no longer exists are removed, and entries whose
+// session is still live are re-scored from its remaining TTL. If ARGV[3] is not empty an expired event is published on
+// that channel for each removed entry. It returns the number of entries examined followed by the IDs that were removed.
+var pruneIndexScript = redis.NewScript(`
+local stale = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local res = {#stale}
+for _, id in ipairs(stale) do
+	local ttl = redis.call('PTTL', id)
+	if ttl == -2 then
+		redis.call('ZREM', KEYS[1], id)
+		if ARGV[3] ~= '' then
+			redis.call('PUBLISH', ARGV[3], cjson.encode({type = 'expired', id = id}))
+		end
+		res[#res + 1] = id
+	elseif ttl == -1 then
+		redis.call('ZADD', KEYS[1], '+inf', id)
+	else
+		redis.call('ZADD', KEYS[1], tonumber(ARGV[1]) + ttl, id)
+	end
+end
+return res
+`)
+
+// peekResult is a single key's entry in the reply to peekScript
+type peekResult struct {
+	payload string
+	found   bool
+	ttl     time.Duration
+}
+
+// parsePeekResult converts the raw reply from peekScript into a peekResult per key
+func parsePeekResult(val interface{}, keys int) ([]peekResult, error) {
+	vals, ok := val.([]interface{})
+	if !ok || len(vals) != keys*2 {
+		return nil, fmt.Errorf("unexpected reply from peek script: %v", val)
+	}
+
+	results := make([]peekResult, keys)
+	for i := range results {
+		if payload, ok := vals[i*2].(string); ok {
+			results[i].payload = payload
+			results[i].found = true
+		}
+
+		ms, ok := vals[i*2+1].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected ttl in reply from peek script: %v", vals[i*2+1])
+		}
+		results[i].ttl = time.Duration(ms) * time.Millisecond
+	}
+
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRotateScript(t *testing.T) {
+	Convey("Given a fake redis holding a session", t, func() {
+		fake := newFakeRedis()
+		client := newFakeClient(fake, Config{})
+		So(client.SetSession(&Session{ID: "1234", Email: "user@email.com", Start: fake.Now()}), ShouldBeNil)
+
+		payload := string(resp)
+		keys := []string{"5678", "user@email.com", expiryIndexKey, "1234"}
+		expiresAt := unixMillis(fake.Now().Add(testTTL))
+		rotate := func() (int64, error) {
+			return rotateScript.Run(fake, keys, payload, testTTL.Milliseconds(), expiresAt).Int64()
+		}
+
+		Convey("When the session is moved to a new ID", func() {
+			moved, err := rotate()
+			So(err, ShouldBeNil)
+
+			Convey("Then it is stored under the new ID and email, and the old ID is removed", func() {
+				So(moved, ShouldEqual, 1)
+				So(fake.Get("5678").Val(), ShouldEqual, payload)
+				So(fake.Get("user@email.com").Val(), ShouldEqual, payload)
+				So(fake.PTTL("5678").Val(), ShouldEqual, testTTL)
+				So(fake.Get("1234").Err(), ShouldEqual, redis.Nil)
+				So(fake.ZScore(expiryIndexKey, "5678").Val(), ShouldEqual, float64(expiresAt))
+				So(fake.ZScore(expiryIndexKey, "1234").Err(), ShouldEqual, redis.Nil)
+			})
+
+			Convey("And repeating the move reports it as moved", func() {
+				moved, err := rotate()
+				So(err, ShouldBeNil)
+				So(moved, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the new ID is taken by another session before the move", func() {
+			So(fake.Set("5678", otherResp, time.Minute).Err(), ShouldBeNil)
+			moved, err := rotate()
+			So(err, ShouldBeNil)
+
+			Convey("Then nothing is moved and the other session is left alone", func() {
+				So(moved, ShouldEqual, 0)
+				So(fake.Get("5678").Val(), ShouldEqual, string(otherResp))
+				So(fake.Get("1234").Err(), ShouldBeNil)
+			})
+		})
+
+		Convey("When the session is removed before the move", func() {
+			So(client.DeleteByID("1234"), ShouldBeNil)
+			moved, err := rotate()
+			So(err, ShouldBeNil)
+
+			Convey("Then nothing is written under the new ID", func() {
+				So(moved, ShouldEqual, -1)
+				So(fake.Get("5678").Err(), ShouldEqual, redis.Nil)
+			})
+		})
+	})
+}
@@ -14,7 +14,15 @@ type Session struct {
 	ID           string    `json:"id"`
 	Email        string    `json:"email"`
 	Start        time.Time `json:"start"`
-	LastAccessed time.Time `json:"lastAccessed"`
+	LastAccessed time.Time `json:"last_accessed"`
+
+	// CSRFSecret is the secret that the session's CSRF tokens are issued and verified with. It is generated by clients
+	// configured with CSRF enabled.
+	CSRFSecret string `json:"csrf_secret,omitempty"`
+
+	// ExpiresAt is when the session will expire if it is not accessed again. It is populated from redis on read
+	// and is not stored as part of the session.
+	ExpiresAt time.Time `json:"-"`
 }
 
 type jsonModel struct {
@@ -22,6 +30,7 @@ type jsonModel struct {
 	Email        string `json:"email"`
 	Start        string `json:"start"`
 	LastAccessed string `json:"last_accessed"`
+	CSRFSecret   string `json:"csrf_secret,omitempty"`
 }
 
 // MarshalJSON is custom JSON marshaller for the Session object ensuring the date fields are marshalled into the correct format
@@ -29,7 +38,13 @@ func (s *Session) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&jsonModel{
 		ID:           s.ID,
 		Email:        s.Email,
-		Start:        s.Start.Format(dateTimeFMT),
-		LastAccessed: s.LastAccessed.Format(dateTimeFMT),
+		Start:        formatTime(s.Start),
+		LastAccessed: formatTime(s.LastAccessed),
+		CSRFSecret:   s.CSRFSecret,
 	})
 }
+
+// formatTime formats t in UTC as it is stored in redis
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFMT)
+}
@@ -31,8 +31,8 @@ func TestSession_MarshalJSON(t *testing.T) {
 			So(err, ShouldBeNil)
 
 			Convey("Then session JSON has the expected field values", func() {
-				expectedStartVal := start.Format(dateTimeFMT)
-				expectedLastAccessedVal := lastAccess.Format(dateTimeFMT)
+				expectedStartVal := start.UTC().Format(dateTimeFMT)
+				expectedLastAccessedVal := lastAccess.UTC().Format(dateTimeFMT)
 
 				assertJSONFieldValue("id", "123", jsonMap)
 				assertJSONFieldValue("email", "test@test.com", jsonMap)
@@ -43,6 +43,26 @@ func TestSession_MarshalJSON(t *testing.T) {
 	})
 }
 
+func TestSession_UnmarshalJSON(t *testing.T) {
+
+	Convey("Given a session stored as JSON", t, func() {
+		b := []byte(`{"id":"123","email":"test@test.com","start":"2021-01-01T10:00:00.000Z","last_accessed":"2021-01-01T11:30:00.000Z"}`)
+
+		Convey("When it is unmarshalled", func() {
+			var s Session
+			err := json.Unmarshal(b, &s)
+			So(err, ShouldBeNil)
+
+			Convey("Then every field is populated in UTC", func() {
+				So(s.ID, ShouldEqual, "123")
+				So(s.Email, ShouldEqual, "test@test.com")
+				So(s.Start, ShouldEqual, time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC))
+				So(s.LastAccessed, ShouldEqual, time.Date(2021, 1, 1, 11, 30, 0, 0, time.UTC))
+			})
+		})
+	})
+}
+
 func assertJSONFieldValue(key, expectedValue string, jsonMap map[string]interface{}) {
 	actualValue, exists := jsonMap[key]
 	So(exists, ShouldBeTrue)
@@ -0,0 +1,62 @@
+package sessionstest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// Server - an in-process redis server for tests, backed by miniredis, with a clock that only moves when FastForward is
+// called. A client created with its address behaves as it would against redis, running the same lua scripts.
+type Server struct {
+	// Password is required by AUTH before any other command, if it is set
+	Password string
+
+	redis *miniredis.Miniredis
+	mu    sync.Mutex
+	now   time.Time
+}
+
+// NewServer - starts a server listening on a random local port
+func NewServer(password string) (*Server, error) {
+	m := miniredis.NewMiniRedis()
+	if password != "" {
+		m.RequireAuth(password)
+	}
+
+	if err := m.Start(); err != nil {
+		return nil, err
+	}
+
+	return &Server{Password: password, redis: m, now: time.Now()}, nil
+}
+
+// Addr - returns the address the server is listening on
+func (s *Server) Addr() string {
+	return s.redis.Addr()
+}
+
+// Now - returns the current time of the server's clock, for use as sessions.Config.Clock
+func (s *Server) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now
+}
+
+// FastForward - moves the server's clock forward by d, expiring any keys whose TTL has passed
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = s.now.Add(d)
+	s.redis.FastForward(d)
+}
+
+// Close - stops the server and closes every connection to it
+func (s *Server) Close() error {
+	s.redis.Close()
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package sessionstest provides an in-process redis stand-in and helpers for testing services that use
+// sessions.Client, without needing a redis server.
+package sessionstest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+)
+
+// DefaultTTL is the session TTL used by New if the config does not set one
+const DefaultTTL = 30 * time.Minute
+
+// password is required by the servers started by New, so that clients authenticate as they would with redis
+const password = "sessionstest"
+
+// Harness - a sessions.Client connected to its own Server, with helpers for testing session flows
+type Harness struct {
+	Client *sessions.Client
+	Server *Server
+
+	t   testing.TB
+	ttl time.Duration
+}
+
+// New - starts a Server and returns a harness with a client connected to it, which are both closed when the test
+// ends. The address, password and clock of cfg are set to those of the server, and its TTL defaults to DefaultTTL.
+func New(t testing.TB, cfg sessions.Config) *Harness {
+	t.Helper()
+
+	server, err := NewServer(password)
+	if err != nil {
+		t.Fatalf("failed to start sessions test server: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	if cfg.TTL == 0 {
+		cfg.TTL = DefaultTTL
+	}
+	cfg.Addr = server.Addr()
+	cfg.Password = password
+	cfg.Clock = server.Now
+
+	client, err := sessions.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to create sessions client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return &Harness{Client: client, Server: server, t: t, ttl: cfg.TTL}
+}
+
+// MustCreateSession - stores a new session for email with a random ID, failing the test if it cannot be stored
+func (h *Harness) MustCreateSession(email string) *sessions.Session {
+	h.t.Helper()
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		h.t.Fatalf("failed to generate session id: %v", err)
+	}
+
+	now := h.Server.Now()
+	s := &sessions.Session{
+		ID:           hex.EncodeToString(id),
+		Email:        email,
+		Start:        now,
+		LastAccessed: now,
+	}
+
+	if err := h.Client.SetSession(s); err != nil {
+		h.t.Fatalf("failed to create session: %v", err)
+	}
+
+	s.ExpiresAt = now.Add(h.ttl)
+
+	return s
+}
+
+// AssertSessionExists - fails the test unless the session with the provided ID exists, and returns it. The session is
+// read without refreshing its TTL.
+func (h *Harness) AssertSessionExists(id string) *sessions.Session {
+	h.t.Helper()
+
+	s, _, err := h.Client.PeekByIDContext(context.Background(), id)
+	if err == sessions.ErrSessionNotFound {
+		h.t.Fatalf("expected session %q to exist but it was not found", id)
+	} else if err != nil {
+		h.t.Fatalf("failed to get session %q: %v", id, err)
+	}
+
+	return s
+}
+
+// AssertSessionNotExists - fails the test if the session with the provided ID exists
+func (h *Harness) AssertSessionNotExists(id string) {
+	h.t.Helper()
+
+	_, _, err := h.Client.PeekByIDContext(context.Background(), id)
+	if err == nil {
+		h.t.Fatalf("expected session %q not to exist but it was found", id)
+	} else if err != sessions.ErrSessionNotFound {
+		h.t.Fatalf("failed to get session %q: %v", id, err)
+	}
+}
+
+// FastForward - moves the server's clock forward by d, expiring any sessions whose TTL has passed
+func (h *Harness) FastForward(d time.Duration) {
+	h.Server.FastForward(d)
+}
@@ -0,0 +1,129 @@
+package sessionstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-redis-clients-go/sessions"
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHarness(t *testing.T) {
+	Convey("Given a harness with a session", t, func() {
+		h := New(t, sessions.Config{Events: true})
+		s := h.MustCreateSession("user@email.com")
+
+		Convey("When the session is looked up", func() {
+			got := h.AssertSessionExists(s.ID)
+
+			Convey("Then it is returned over the redis protocol", func() {
+				So(got.ID, ShouldEqual, s.ID)
+				So(got.Email, ShouldEqual, "user@email.com")
+				So(got.ExpiresAt, ShouldEqual, s.ExpiresAt)
+			})
+		})
+
+		Convey("When the clock is fast forwarded past the TTL", func() {
+			h.FastForward(DefaultTTL)
+
+			Convey("Then the session has expired", func() {
+				h.AssertSessionNotExists(s.ID)
+				_, err := h.Client.GetByEmail("user@email.com")
+				So(err, ShouldEqual, redis.Nil)
+			})
+
+			Convey("And pruning the index removes it", func() {
+				removed, err := h.Client.PruneIndex(context.Background())
+				So(err, ShouldBeNil)
+				So(removed, ShouldResemble, []string{s.ID})
+			})
+		})
+
+		Convey("When the session is read before it expires", func() {
+			h.FastForward(DefaultTTL - time.Minute)
+			_, err := h.Client.GetByID(s.ID)
+			So(err, ShouldBeNil)
+			h.FastForward(2 * time.Minute)
+
+			Convey("Then its TTL was refreshed", func() {
+				_, ttl, err := h.Client.PeekByEmail("user@email.com")
+				So(err, ShouldBeNil)
+				So(ttl, ShouldEqual, DefaultTTL-2*time.Minute)
+			})
+		})
+
+		Convey("When sessions are listed", func() {
+			other := h.MustCreateSession("other@email.com")
+			list, cursor, err := h.Client.ListSessions(context.Background(), 0, 100)
+			So(err, ShouldBeNil)
+
+			Convey("Then each session is listed once", func() {
+				So(cursor, ShouldEqual, 0)
+				ids := []string{}
+				for _, l := range list {
+					ids = append(ids, l.ID)
+				}
+				So(ids, ShouldHaveLength, 2)
+				So(ids, ShouldContain, s.ID)
+				So(ids, ShouldContain, other.ID)
+			})
+		})
+
+		Convey("When events are subscribed to and the session is deleted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events, err := h.Client.Subscribe(ctx)
+			So(err, ShouldBeNil)
+
+			So(h.Client.DeleteByID(s.ID), ShouldBeNil)
+
+			Convey("Then the revocation is received", func() {
+				select {
+				case e := <-events:
+					So(e, ShouldResemble, sessions.Event{Type: sessions.SessionRevoked, SessionID: s.ID})
+				case <-time.After(time.Second):
+					So("no event received", ShouldBeEmpty)
+				}
+				h.AssertSessionNotExists(s.ID)
+			})
+		})
+	})
+}
+
+func TestServer(t *testing.T) {
+	Convey("Given a server with a password", t, func() {
+		server, err := NewServer("secret")
+		So(err, ShouldBeNil)
+		defer server.Close()
+
+		Convey("When a client connects with the wrong password", func() {
+			client := redis.NewClient(&redis.Options{Addr: server.Addr(), Password: "wrong"})
+			defer client.Close()
+
+			Convey("Then its commands are rejected", func() {
+				So(client.Ping().Err(), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a client connects with the right password", func() {
+			client := redis.NewClient(&redis.Options{Addr: server.Addr(), Password: "secret"})
+			defer client.Close()
+
+			Convey("Then it can run commands", func() {
+				So(client.Ping().Val(), ShouldEqual, "PONG")
+				So(client.Set("key", "value", time.Second).Err(), ShouldBeNil)
+				So(client.Get("key").Val(), ShouldEqual, "value")
+				So(client.PTTL("key").Val(), ShouldEqual, time.Second)
+
+				server.FastForward(time.Second)
+				So(client.Get("key").Err(), ShouldEqual, redis.Nil)
+			})
+
+			Convey("And unknown commands return an error", func() {
+				So(client.Do("notacommand", "key").Err(), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package sessions
+
+import (
+	"time"
+)
+
+// defaultSlowThreshold is how long an operation can take before it is reported as slow if no threshold is configured
+const defaultSlowThreshold = 250 * time.Millisecond
+
+// KeyNamespace - the kind of redis key an operation acts on
+type KeyNamespace string
+
+// Possible values for KeyNamespace
+const (
+	KeyNamespaceID    KeyNamespace = "id"
+	KeyNamespaceEmail KeyNamespace = "email"
+	KeyNamespaceIndex KeyNamespace = "index"
+	KeyNamespaceAll   KeyNamespace = "all"
+	KeyNamespaceNone  KeyNamespace = "none"
+)
+
+// opNamespaces maps each operation to the namespace of the keys it acts on
+var opNamespaces = map[string]KeyNamespace{
+	opSetSession:  KeyNamespaceID,
+	opGetByID:     KeyNamespaceID,
+	opGetByEmail:  KeyNamespaceEmail,
+	opPeekByID:    KeyNamespaceID,
+	opPeekByEmail: KeyNamespaceEmail,
+	opTTL:         KeyNamespaceID,
+	opGetManyByID: KeyNamespaceID,
+	opDeleteByID:  KeyNamespaceID,
+	opDeleteAll:   KeyNamespaceAll,
+	opPing:        KeyNamespaceNone,
+	opExpire:      KeyNamespaceNone,
+	opList:        KeyNamespaceAll,
+	opRevokeWhere: KeyNamespaceAll,
+	opPruneIndex:  KeyNamespaceIndex,
+	opStats:       KeyNamespaceIndex,
+	opRotateID:    KeyNamespaceID,
+}
+
+// SlowOperation - details of an operation that took longer than the slow threshold
+type SlowOperation struct {
+	Command      string
+	Duration     time.Duration
+	KeyNamespace KeyNamespace
+}
+
+// isSlow reports whether an operation that took elapsed is slow
+func (c *Client) isSlow(elapsed time.Duration) bool {
+	threshold := c.slowThreshold
+	if threshold == 0 {
+		threshold = defaultSlowThreshold
+	}
+
+	return elapsed > threshold
+}
+
+// reportSlow counts a slow operation and passes it to the callback, if one is configured
+func (c *Client) reportSlow(op string, elapsed time.Duration) {
+	slow := SlowOperation{
+		Command:      op,
+		Duration:     elapsed,
+		KeyNamespace: opNamespaces[op],
+	}
+
+	c.metrics.slow(slow)
+
+	if c.onSlowOperation != nil {
+		c.onSlowOperation(slow)
+	}
+}
@@ -0,0 +1,78 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewClient_SlowThreshold(t *testing.T) {
+	Convey("Given a negative slow threshold", t, func() {
+
+		Convey("When the client is created", func() {
+			c, err := NewClient(Config{
+				Addr:          "123.0.0.1",
+				Password:      "1234",
+				TTL:           testTTL,
+				SlowThreshold: -time.Second,
+			})
+
+			Convey("Then the client will not be created and the invalid slow threshold error is returned", func() {
+				So(c, ShouldBeNil)
+				So(err, ShouldEqual, ErrInvalidSlow)
+			})
+		})
+	})
+}
+
+func TestClient_SlowOperations(t *testing.T) {
+	Convey("Given a client with a slow threshold and callback", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		client.metrics = newMetrics()
+		client.slowThreshold = 5 * time.Millisecond
+
+		var slow []SlowOperation
+		client.onSlowOperation = func(op SlowOperation) {
+			slow = append(slow, op)
+		}
+
+		Convey("When an operation runs past the threshold", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				time.Sleep(10 * time.Millisecond)
+				return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+			}
+			_, err := client.GetByEmail("user@email.com")
+			So(err, ShouldBeNil)
+
+			Convey("Then the callback is called with the command, duration and key namespace", func() {
+				So(slow, ShouldHaveLength, 1)
+				So(slow[0].Command, ShouldEqual, opGetByEmail)
+				So(slow[0].Duration, ShouldBeGreaterThanOrEqualTo, 10*time.Millisecond)
+				So(slow[0].KeyNamespace, ShouldEqual, KeyNamespaceEmail)
+			})
+
+			Convey("And the slow operation is counted", func() {
+				So(testutil.ToFloat64(client.metrics.slowOps.WithLabelValues(opGetByEmail, string(KeyNamespaceEmail))), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When an operation completes within the threshold", func() {
+			mockRedisClient.PingFunc = func() *redis.StatusCmd {
+				return redis.NewStatusResult("PONG", nil)
+			}
+			So(client.Ping(), ShouldBeNil)
+
+			Convey("Then it is not reported", func() {
+				So(slow, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Stats - point in time statistics about the sessions held in redis
+type Stats struct {
+	// ActiveSessions is the number of sessions that have not yet expired
+	ActiveSessions int64
+}
+
+// Stats - returns statistics about the sessions held in redis. The count is read from the expiry index in a single
+// ZCOUNT call rather than by scanning the keyspace, so it is cheap enough to poll for dashboards.
+func (c *Client) Stats(ctx context.Context) (stats Stats, err error) {
+	ctx, op := c.startOp(ctx, opStats, "")
+	defer func() { op.end(err) }()
+
+	if err := ctx.Err(); err != nil {
+		return Stats{}, err
+	}
+
+	var active int64
+	err = c.do(ctx, true, func() (err error) {
+		active, err = c.countActive()
+		return err
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("redis client.ZCount returned an unexpected error: %w", err)
+	}
+
+	return Stats{ActiveSessions: active}, nil
+}
+
+// countActive counts the entries in the expiry index that have not expired
+func (c *Client) countActive() (int64, error) {
+	return c.client.ZCount(expiryIndexKey, strconv.FormatInt(unixMillis(c.now()), 10), "+inf").Result()
+}
@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Stats(t *testing.T) {
+	Convey("Given the expiry index holds live sessions", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.ZCountFunc = func(key, min, max string) *redis.IntCmd {
+			return redis.NewIntResult(3, nil)
+		}
+
+		Convey("When client.Stats is called", func() {
+			stats, err := client.Stats(context.Background())
+
+			Convey("Then the sessions expiring after now are counted", func() {
+				So(err, ShouldBeNil)
+				So(mockRedisClient.ZCountCalls(), ShouldHaveLength, 1)
+				So(mockRedisClient.ZCountCalls()[0].Key, ShouldEqual, expiryIndexKey)
+				So(mockRedisClient.ZCountCalls()[0].Max, ShouldEqual, "+inf")
+
+				min, err := strconv.ParseInt(mockRedisClient.ZCountCalls()[0].Min, 10, 64)
+				So(err, ShouldBeNil)
+				So(min, ShouldAlmostEqual, unixMillis(time.Now()), 1000)
+			})
+
+			Convey("And the count of active sessions is returned", func() {
+				So(stats.ActiveSessions, ShouldEqual, 3)
+			})
+		})
+	})
+
+	Convey("Given redis client.ZCount returns an error", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		mockRedisClient.ZCountFunc = func(key, min, max string) *redis.IntCmd {
+			return redis.NewIntResult(0, errors.New("some redis error"))
+		}
+
+		Convey("When client.Stats is called", func() {
+			_, err := client.Stats(context.Background())
+
+			Convey("Then the error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "redis client.ZCount returned an unexpected error: some redis error")
+			})
+		})
+	})
+}
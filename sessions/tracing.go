@@ -0,0 +1,124 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by the client
+const tracerName = "github.com/ONSdigital/dp-redis-clients-go/sessions"
+
+// Span attribute keys. Session IDs, emails and keys are never recorded as they identify users.
+const (
+	attrOperation      = attribute.Key("sessions.operation")
+	attrHit            = attribute.Key("sessions.hit")
+	attrLocalCacheHit  = attribute.Key("sessions.local_cache_hit")
+	attrRequested      = attribute.Key("sessions.requested")
+	attrFound          = attribute.Key("sessions.found")
+	attrRevoked        = attribute.Key("sessions.revoked")
+	attrRemoved        = attribute.Key("sessions.removed")
+	attrError          = attribute.Key("error")
+	attrRetryAttempt   = attribute.Key("sessions.retry.attempt")
+	attrDBSystem       = attribute.Key("db.system")
+	dbSystemRedisValue = "redis"
+)
+
+// Names of the operations the client traces
+const (
+	opSetSession  = "SetSession"
+	opGetByID     = "GetByID"
+	opGetByEmail  = "GetByEmail"
+	opPeekByID    = "PeekByID"
+	opPeekByEmail = "PeekByEmail"
+	opTTL         = "TTL"
+	opGetManyByID = "GetManyByID"
+	opDeleteByID  = "DeleteByID"
+	opDeleteAll   = "DeleteAll"
+	opPing        = "Ping"
+	opExpire      = "Expire"
+	opList        = "ListSessions"
+	opRevokeWhere = "RevokeWhere"
+	opPruneIndex  = "PruneIndex"
+	opStats       = "Stats"
+	opRotateID    = "RotateID"
+)
+
+// operation - a client operation in progress, which is traced, measured and logged until it ends
+type operation struct {
+	c     *Client
+	ctx   context.Context
+	name  string
+	key   string
+	span  trace.Span
+	start time.Time
+}
+
+// operationKey is the context key of the operation in progress
+type operationKey struct{}
+
+// startOp starts a span for the named operation as a child of any span in ctx. key is the session ID or email the
+// operation acts on, if any, which is only ever logged in redacted form.
+func (c *Client) startOp(ctx context.Context, name, key string) (context.Context, *operation) {
+	tracer := c.tracer
+	if tracer == nil {
+		tracer = otel.Tracer(tracerName)
+	}
+
+	ctx, span := tracer.Start(ctx, "sessions."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrDBSystem.String(dbSystemRedisValue), attrOperation.String(name)),
+	)
+
+	o := &operation{c: c, name: name, key: key, span: span, start: time.Now()}
+	o.ctx = context.WithValue(ctx, operationKey{}, o)
+
+	return o.ctx, o
+}
+
+// end records the outcome of the operation on its span, in the client metrics and in the log, then ends the span.
+// ErrSessionNotFound is a miss rather than an error.
+func (o *operation) end(err error, attrs ...attribute.KeyValue) {
+	elapsed := time.Since(o.start)
+
+	o.c.metrics.observe(o.name, elapsed, err)
+	o.c.logOperation(o.ctx, o, elapsed, err)
+
+	if o.c.isSlow(elapsed) {
+		o.c.reportSlow(o.name, elapsed)
+	}
+
+	o.span.SetAttributes(attrs...)
+
+	if err != nil && err != ErrSessionNotFound {
+		o.span.SetAttributes(attrError.Bool(true))
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	} else {
+		for _, kv := range attrs {
+			if kv.Key != attrHit {
+				continue
+			}
+			if kv.Value.AsBool() {
+				o.c.metrics.lookup(o.name, 1, 0)
+			} else {
+				o.c.metrics.lookup(o.name, 0, 1)
+			}
+		}
+	}
+
+	o.span.End()
+}
+
+// logData returns the fields identifying the operation in log events, with its key redacted if it can be
+func (o *operation) logData() map[string]interface{} {
+	data := map[string]interface{}{"operation": o.name}
+	if key := o.c.RedactKey(o.key); o.key != "" && key != "" {
+		data["key"] = key
+	}
+	return data
+}
@@ -0,0 +1,92 @@
+package sessions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_Tracing(t *testing.T) {
+	Convey("Given a client with a tracer provider", t, func() {
+		mockRedisClient, client := setUpMocks(
+			*redis.NewStatusCmd(),
+			*redis.NewStringCmd(),
+			*redis.NewStatusCmd(),
+			*redis.NewBoolCmd(),
+		)
+		recorder := tracetest.NewSpanRecorder()
+		client.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(tracerName)
+
+		Convey("When a session is found by ID", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				return redis.NewCmdResult([]interface{}{string(resp), testTTL.Milliseconds()}, nil)
+			}
+			_, err := client.GetByID("1234")
+			So(err, ShouldBeNil)
+
+			Convey("Then a span is recorded for the operation with a hit", func() {
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spans[0].Name(), ShouldEqual, "sessions.GetByID")
+				So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+
+				attrs := spanAttributes(spans[0])
+				So(attrs[string(attrOperation)], ShouldEqual, opGetByID)
+				So(attrs[string(attrHit)], ShouldEqual, "true")
+			})
+
+			Convey("And no session identifiers are recorded", func() {
+				for _, v := range spanAttributes(recorder.Ended()[0]) {
+					So(strings.Contains(v, "1234"), ShouldBeFalse)
+					So(strings.Contains(v, "user@email.com"), ShouldBeFalse)
+				}
+			})
+		})
+
+		Convey("When a session is not found", func() {
+			mockRedisClient.EvalShaFunc = func(sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+				return redis.NewCmdResult(nil, redis.Nil)
+			}
+			_, err := client.GetByID("1234")
+			So(err, ShouldEqual, redis.Nil)
+
+			Convey("Then the span records a miss without an error status", func() {
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+				So(spanAttributes(spans[0])[string(attrHit)], ShouldEqual, "false")
+			})
+		})
+
+		Convey("When redis returns an error", func() {
+			mockRedisClient.PingFunc = func() *redis.StatusCmd {
+				return redis.NewStatusResult("", errors.New("NOAUTH Authentication required."))
+			}
+			err := client.Ping()
+			So(err, ShouldNotBeNil)
+
+			Convey("Then the span is marked as failed and the error is recorded", func() {
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spans[0].Name(), ShouldEqual, "sessions.Ping")
+				So(spans[0].Status().Code, ShouldEqual, codes.Error)
+				So(spans[0].Events(), ShouldHaveLength, 1)
+				So(spans[0].Events()[0].Name, ShouldEqual, "exception")
+			})
+		})
+	})
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}